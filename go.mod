@@ -8,4 +8,6 @@ require (
 	github.com/olekukonko/tablewriter v0.0.5
 	github.com/spf13/cobra v1.2.1
 	github.com/spf13/viper v1.9.0
+	golang.org/x/sys v0.0.0-20210823070655-63515b42dcdf
+	gopkg.in/yaml.v2 v2.4.0
 )
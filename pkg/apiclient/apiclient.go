@@ -18,64 +18,120 @@ package apiclient
 
 import (
 	"context"
-	"errors"
+	"jenkinsctl/pkg/audit"
+	"net/http"
+	"sync"
 
 	"github.com/bndr/gojenkins"
-	"github.com/spf13/viper"
 )
 
 type ApiClient struct {
 	Jenkins      *gojenkins.Jenkins
 	Ctx          context.Context
 	ClientConfig *ApiClientConfig
+	// Audit, when set, is called with every action run on the jobs, the dry
+	// runs excepted.
+	Audit func(entry *audit.Entry)
 }
 
+// ApiClientConfig is the configuration of the Jenkins server, read by the
+// command line from its configuration file.
 type ApiClientConfig struct {
-	address              string
-	username             string
-	token                string
+	Address              string
+	Username             string
+	Token                string
 	MaxConcurentRequests int
 }
 
-func (clt *ApiClient) getConfig() error {
-
-	viper.SetDefault("jenkins.max_concurent", 3)
-
-	clt.ClientConfig = &ApiClientConfig{
-		address:              viper.GetString("jenkins.addr"),
-		username:             viper.GetString("jenkins.user"),
-		token:                viper.GetString("jenkins.token"),
-		MaxConcurentRequests: viper.GetInt("jenkins.max_concurent"),
-	}
-	missingConfig := false
-	var errorMessage = "\n"
-	if clt.ClientConfig.address == "" {
-		missingConfig = true
-		errorMessage = errorMessage + "jenkins server address not defined\n"
+// Connect configures the client from config and checks that Jenkins can be
+// reached.
+func (clt *ApiClient) Connect(config *ApiClientConfig) error {
+	if config.MaxConcurentRequests <= 0 {
+		config.MaxConcurentRequests = 1
 	}
-	if clt.ClientConfig.username == "" {
-		missingConfig = true
-		errorMessage = errorMessage + "jenkins server username not defined\n"
+	clt.ClientConfig = config
+	clt.Ctx = context.Background()
+	clt.Jenkins = gojenkins.CreateJenkins(nil, config.Address, config.Username, config.Token)
+	_, err := clt.Jenkins.Init(clt.Ctx)
+	return err
+}
+
+// NewClient returns a client of the Jenkins server at address without reading
+// the configuration nor connecting to Jenkins, httpClient being the default
+// HTTP client when nil.
+func NewClient(
+	address string, username string, token string, maxConcurentRequests int, httpClient *http.Client,
+) *ApiClient {
+	if maxConcurentRequests <= 0 {
+		maxConcurentRequests = 1
 	}
-	if clt.ClientConfig.token == "" {
-		missingConfig = true
-		errorMessage = errorMessage + "jenkins server token not defined\n"
+	return &ApiClient{
+		Jenkins: gojenkins.CreateJenkins(httpClient, address, username, token),
+		Ctx:     context.Background(),
+		ClientConfig: &ApiClientConfig{
+			Address:              address,
+			Username:             username,
+			Token:                token,
+			MaxConcurentRequests: maxConcurentRequests,
+		},
 	}
-	if missingConfig {
-		return errors.New(errorMessage)
+}
+
+// WithContext returns a copy of the client whose requests are bound to ctx.
+func (clt *ApiClient) WithContext(ctx context.Context) *ApiClient {
+	copy := *clt
+	copy.Ctx = ctx
+	return &copy
+}
+
+// WithoutAudit returns a copy of the client whose actions are not passed to
+// the audit hook, for the callers running an action in several steps and
+// recording it once.
+func (clt *ApiClient) WithoutAudit() *ApiClient {
+	copy := *clt
+	copy.Audit = nil
+	return &copy
+}
+
+// NewConnectedClient returns a client connected to the Jenkins server of
+// config.
+func NewConnectedClient(config *ApiClientConfig) (*ApiClient, error) {
+	clt := &ApiClient{}
+	if err := clt.Connect(config); err != nil {
+		return nil, err
 	}
-	return nil
+	return clt, nil
 }
 
-func (clt *ApiClient) Initialize() {
-	if err := clt.getConfig(); err != nil {
-		panic(err)
+// RunConcurrently calls fn for each index from 0 to count, with at most
+// MaxConcurentRequests calls running at the same time. fn receives the index
+// so that it can store its result without any further synchronization.
+func (clt *ApiClient) RunConcurrently(count int, fn func(i int)) {
+	indexes := make(chan int, count)
+	for i := 0; i < count; i++ {
+		indexes <- i
 	}
-	clt.Ctx = context.Background()
-	clt.Jenkins = gojenkins.CreateJenkins(
-		nil, clt.ClientConfig.address, clt.ClientConfig.username, clt.ClientConfig.token,
-	)
-	if _, err := clt.Jenkins.Init(clt.Ctx); err != nil {
-		panic(err)
+	close(indexes)
+
+	var wg sync.WaitGroup
+	for i := 0; i < clt.ClientConfig.MaxConcurentRequests; i++ {
+		wg.Add(1)
+		go func() {
+			for i := range indexes {
+				fn(i)
+			}
+			wg.Done()
+		}()
 	}
+	wg.Wait()
+}
+
+// Address returns the address of the Jenkins server of the client.
+func (clt *ApiClient) Address() string {
+	return clt.ClientConfig.Address
+}
+
+// Username returns the Jenkins user the client is authenticated as.
+func (clt *ApiClient) Username() string {
+	return clt.ClientConfig.Username
 }
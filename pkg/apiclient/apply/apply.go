@@ -0,0 +1,308 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/manifest"
+	"sort"
+	"strings"
+
+	"github.com/bndr/gojenkins"
+)
+
+const (
+	CHANGE_CREATE = "create"
+	CHANGE_UPDATE = "update"
+	CHANGE_DELETE = "delete"
+)
+
+type Change struct {
+	Action  string
+	Name    string
+	Folder  bool
+	Current string
+	Config  string
+}
+
+type Plan struct {
+	Changes   []Change
+	Unchanged int
+}
+
+// parentFolders returns the folders containing the jobs, parents before
+// their sub-folders.
+func parentFolders(manifestJobs []manifest.Job) []string {
+	folders := map[string]bool{}
+	for _, job := range manifestJobs {
+		_, parents := jobs.SplitJobPath(job.FullName())
+		for i := range parents {
+			folders[strings.Join(parents[:i+1], "/")] = true
+		}
+	}
+	names := []string{}
+	for name := range folders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func folderExists(clt *apiclient.ApiClient, fullName string) (bool, error) {
+	name, parents := jobs.SplitJobPath(fullName)
+	_, err := clt.Jenkins.GetFolder(clt.Ctx, name, parents...)
+	if err != nil && strings.HasSuffix(err.Error(), "404") {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (plan *Plan) addFolders(clt *apiclient.ApiClient, manifestJobs []manifest.Job) error {
+	for _, folder := range parentFolders(manifestJobs) {
+		exists, err := folderExists(clt, folder)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			plan.Changes = append(plan.Changes, Change{
+				Action: CHANGE_CREATE, Name: folder, Folder: true,
+			})
+		}
+	}
+	return nil
+}
+
+func planJob(clt *apiclient.ApiClient, job manifest.Job) (*Change, error) {
+	config, err := job.Render()
+	if err != nil {
+		return nil, err
+	}
+	change := &Change{Name: job.FullName(), Config: config}
+
+	change.Current, err = jobs.GetJobConfig(clt, job.FullName())
+	if err != nil && err.Error() == "404" {
+		change.Action = CHANGE_CREATE
+		return change, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := jobs.CanonicalizeConfig(change.Current, true)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := jobs.CanonicalizeConfig(config, true)
+	if err != nil {
+		return nil, err
+	}
+	if current == expected {
+		return nil, nil
+	}
+	change.Action = CHANGE_UPDATE
+	return change, nil
+}
+
+func (plan *Plan) addJobs(clt *apiclient.ApiClient, manifestJobs []manifest.Job) error {
+	changes := make([]*Change, len(manifestJobs))
+	errs := make([]error, len(manifestJobs))
+	clt.RunConcurrently(len(manifestJobs), func(i int) {
+		changes[i], errs[i] = planJob(clt, manifestJobs[i])
+	})
+	for i, change := range changes {
+		if errs[i] != nil {
+			return fmt.Errorf("job %s: %s", manifestJobs[i].FullName(), errs[i])
+		}
+		if change == nil {
+			plan.Unchanged++
+			continue
+		}
+		plan.Changes = append(plan.Changes, *change)
+	}
+	return nil
+}
+
+// listFolderJobs returns the full name of the jobs of a folder, or of the
+// root of Jenkins when folder is empty. Only the jobs of the classes a manifest
+// can describe are returned, so that the sub-folders, multibranch projects and
+// other items are never pruned.
+func listFolderJobs(clt *apiclient.ApiClient, folder string) ([]string, error) {
+	var innerJobs []gojenkins.InnerJob
+	if folder == "" {
+		var err error
+		innerJobs, err = clt.Jenkins.GetAllJobNames(clt.Ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		exists, err := folderExists(clt, folder)
+		if err != nil || !exists {
+			return nil, err
+		}
+		name, parents := jobs.SplitJobPath(folder)
+		jenkinsFolder, err := clt.Jenkins.GetFolder(clt.Ctx, name, parents...)
+		if err != nil {
+			return nil, err
+		}
+		innerJobs = jenkinsFolder.Raw.Jobs
+	}
+
+	names := []string{}
+	for _, innerJob := range innerJobs {
+		if !manifest.IsManifestClass(innerJob.Class) {
+			continue
+		}
+		if folder == "" {
+			names = append(names, innerJob.Name)
+		} else {
+			names = append(names, folder+"/"+innerJob.Name)
+		}
+	}
+	return names, nil
+}
+
+// managedFolders returns the folders where the manifests define jobs, the
+// root of Jenkins being the empty folder, sorted by name.
+func managedFolders(manifestJobs []manifest.Job) []string {
+	folders := map[string]bool{}
+	for _, job := range manifestJobs {
+		_, parents := jobs.SplitJobPath(job.FullName())
+		folders[strings.Join(parents, "/")] = true
+	}
+	names := []string{}
+	for folder := range folders {
+		names = append(names, folder)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// prunedJobs returns the jobs of names which are not described in the
+// manifests.
+func prunedJobs(names []string, manifestJobs []manifest.Job) []string {
+	managed := map[string]bool{}
+	for _, job := range manifestJobs {
+		managed[job.FullName()] = true
+	}
+	pruned := []string{}
+	for _, name := range names {
+		if !managed[name] {
+			pruned = append(pruned, name)
+		}
+	}
+	return pruned
+}
+
+// addPrunedJobs plans the deletion of the jobs which are not described in
+// the manifests, in the folders where the manifests define jobs.
+func (plan *Plan) addPrunedJobs(clt *apiclient.ApiClient, manifestJobs []manifest.Job) error {
+	for _, folder := range managedFolders(manifestJobs) {
+		names, err := listFolderJobs(clt, folder)
+		if err != nil {
+			return err
+		}
+		for _, name := range prunedJobs(names, manifestJobs) {
+			plan.Changes = append(plan.Changes, Change{Action: CHANGE_DELETE, Name: name})
+		}
+	}
+	return nil
+}
+
+// ComputePlan compares the jobs described by the manifests with the jobs of
+// the Jenkins server, without modifying them. With prune, the jobs of the
+// managed folders which are not described by the manifests are deleted.
+func ComputePlan(
+	clt *apiclient.ApiClient, manifestJobs []manifest.Job, prune bool,
+) (*Plan, error) {
+	plan := &Plan{}
+	if err := plan.addFolders(clt, manifestJobs); err != nil {
+		return nil, err
+	}
+	if err := plan.addJobs(clt, manifestJobs); err != nil {
+		return nil, err
+	}
+	if prune {
+		if err := plan.addPrunedJobs(clt, manifestJobs); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// Count returns the number of changes of the plan doing action.
+func (plan *Plan) Count(action string) int {
+	count := 0
+	for _, change := range plan.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+func (change *Change) apply(clt *apiclient.ApiClient) error {
+	name, parents := jobs.SplitJobPath(change.Name)
+	switch {
+	case change.Action == CHANGE_CREATE && change.Folder:
+		_, err := clt.Jenkins.CreateFolder(clt.Ctx, name, parents...)
+		return err
+	case change.Action == CHANGE_CREATE:
+		_, err := clt.Jenkins.CreateJobInFolder(clt.Ctx, change.Config, name, parents...)
+		return err
+	case change.Action == CHANGE_UPDATE:
+		return jobs.NewJenkinsJob(clt, change.Name).UpdateConfig(clt.Ctx, change.Config)
+	case change.Action == CHANGE_DELETE:
+		_, err := jobs.NewJenkinsJob(clt, change.Name).Delete(clt.Ctx)
+		return err
+	}
+	return nil
+}
+
+// Apply performs the changes of the plan in order, so that the folders are
+// created before their jobs, and returns the outcome of each change until an
+// error occurs.
+func (plan *Plan) Apply(clt *apiclient.ApiClient) (results []jobs.ActionResult, err error) {
+	names := make([]string, len(plan.Changes))
+	for i, change := range plan.Changes {
+		names[i] = change.Name
+	}
+	defer func() { jobs.RecordAudit(clt, "apply", names, results, err) }()
+
+	results = []jobs.ActionResult{}
+	for _, change := range plan.Changes {
+		if err := change.apply(clt); err != nil {
+			return results, fmt.Errorf("%s %s: %s", change.Action, change.Name, err)
+		}
+		kind := "job"
+		if change.Folder {
+			kind = "folder"
+		}
+		results = append(results, jobs.ActionResult{
+			Job:     change.Name,
+			Status:  jobs.ACTION_STATUS_DONE,
+			Message: fmt.Sprintf("%s %s is now %sd", kind, change.Name, change.Action),
+		})
+	}
+	return results, nil
+}
+
+// HasChanges reports whether the plan modifies at least one job.
+func (plan *Plan) HasChanges() bool {
+	return len(plan.Changes) > 0
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"jenkinsctl/pkg/manifest"
+	"reflect"
+	"testing"
+)
+
+var testJobs = []manifest.Job{
+	{Name: "app"},
+	{Name: "deploy", Folder: "team/prod"},
+	{Name: "build", Folder: "team"},
+	{Name: "test", Folder: "/team/"},
+}
+
+func TestParentFolders(t *testing.T) {
+	want := []string{"team", "team/prod"}
+	if got := parentFolders(testJobs); !reflect.DeepEqual(got, want) {
+		t.Errorf("parentFolders() = %v, want %v", got, want)
+	}
+}
+
+func TestManagedFolders(t *testing.T) {
+	tests := []struct {
+		jobs []manifest.Job
+		want []string
+	}{
+		{nil, []string{}},
+		{testJobs[:1], []string{""}},
+		{testJobs[1:2], []string{"team/prod"}},
+		{testJobs, []string{"", "team", "team/prod"}},
+	}
+	for _, test := range tests {
+		if got := managedFolders(test.jobs); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("managedFolders(%v) = %v, want %v", test.jobs, got, test.want)
+		}
+	}
+}
+
+func TestPrunedJobs(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  []string
+	}{
+		{[]string{}, []string{}},
+		{[]string{"app", "legacy"}, []string{"legacy"}},
+		{[]string{"team/build", "team/test", "team/old"}, []string{"team/old"}},
+		{[]string{"team/prod/deploy", "team/prod/app"}, []string{"team/prod/app"}},
+	}
+	for _, test := range tests {
+		if got := prunedJobs(test.names, testJobs); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("prunedJobs(%v) = %v, want %v", test.names, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const MANIFEST_FILE = "manifest.json"
+
+var xmlHeaderRegex = regexp.MustCompile(`^<\?xml.*\?>`)
+
+type Item struct {
+	Name         string    `json:"name"`
+	Class        string    `json:"class"`
+	Path         string    `json:"path"`
+	Sha256       string    `json:"sha256"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+}
+
+type Manifest struct {
+	Server    string    `json:"server"`
+	CreatedAt time.Time `json:"created_at"`
+	Items     []Item    `json:"items"`
+}
+
+// itemPath returns the path of the config.xml of an item, following the
+// layout of the JENKINS_HOME directory ("jobs/folder/jobs/job/config.xml").
+func itemPath(fullName string) string {
+	names := strings.Split(fullName, "/")
+	parts := []string{}
+	for _, name := range names {
+		parts = append(parts, "jobs", name)
+	}
+	return filepath.Join(append(parts, "config.xml")...)
+}
+
+func (item *Item) IsFolder() bool {
+	return item.Class == jobs.FOLDER_CLASS
+}
+
+func (item *Item) depth() int {
+	return strings.Count(item.Name, "/")
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeConfig removes the parts of a config.xml that change without any
+// change of the configuration itself.
+func normalizeConfig(config string) string {
+	return strings.TrimSpace(xmlHeaderRegex.ReplaceAllString(strings.TrimSpace(config), ""))
+}
+
+// formatConfig indents a config.xml so that the differences between two
+// configurations are shown line by line.
+func formatConfig(config string) string {
+	formatted, err := jobs.CanonicalizeConfig(config, false)
+	if err != nil {
+		return normalizeConfig(config) + "\n"
+	}
+	return formatted
+}
+
+func ReadManifest(dir string) (*Manifest, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, MANIFEST_FILE))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (manifest *Manifest) write(dir string) error {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, MANIFEST_FILE), content, 0644)
+}
+
+func newManifest(clt *apiclient.ApiClient) *Manifest {
+	return &Manifest{
+		Server:    clt.Jenkins.Server,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bndr/gojenkins"
+)
+
+// listItems returns every item of the Jenkins server, including the content
+// of the folders.
+func listItems(clt *apiclient.ApiClient) ([]Item, error) {
+	innerJobs, err := clt.Jenkins.GetAllJobNames(clt.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return listFolderItems(clt, "", innerJobs)
+}
+
+func listFolderItems(
+	clt *apiclient.ApiClient, parent string, innerJobs []gojenkins.InnerJob,
+) ([]Item, error) {
+	items := []Item{}
+	for _, innerJob := range innerJobs {
+		name := innerJob.Name
+		if parent != "" {
+			name = parent + "/" + name
+		}
+		items = append(items, Item{Name: name, Class: innerJob.Class, Path: itemPath(name)})
+		if innerJob.Class != jobs.FOLDER_CLASS {
+			continue
+		}
+
+		folderName, parents := jobs.SplitJobPath(name)
+		folder, err := clt.Jenkins.GetFolder(clt.Ctx, folderName, parents...)
+		if err != nil {
+			return nil, err
+		}
+		children, err := listFolderItems(clt, name, folder.Raw.Jobs)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, children...)
+	}
+	return items, nil
+}
+
+func (item *Item) download(clt *apiclient.ApiClient, dir string) error {
+	config, err := jobs.GetJobConfig(clt, item.Name)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, item.Path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, []byte(config), 0644); err != nil {
+		return err
+	}
+	item.Sha256 = checksum([]byte(config))
+	item.DownloadedAt = time.Now()
+	return nil
+}
+
+// Create saves the config.xml of every job and folder in dir, along with a
+// manifest listing their checksums, and returns the outcome for each of them.
+// The manifest is not written when a job could not be saved.
+func Create(clt *apiclient.ApiClient, dir string) ([]jobs.ActionResult, error) {
+	manifest := newManifest(clt)
+	items, err := listItems(clt)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(items))
+	clt.RunConcurrently(len(items), func(i int) {
+		errs[i] = items[i].download(clt, dir)
+	})
+	results := make([]jobs.ActionResult, len(items))
+	failed := 0
+	for i, err := range errs {
+		results[i] = jobs.ActionResult{
+			Job:     items[i].Name,
+			Status:  jobs.ACTION_STATUS_DONE,
+			Message: fmt.Sprintf("job %s is now saved", items[i].Name),
+		}
+		if err != nil {
+			failed++
+			results[i].Status = jobs.ACTION_STATUS_FAILED
+			results[i].Message = fmt.Sprintf("job %s failed: %s", items[i].Name, err)
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d jobs could not be saved", failed)
+	}
+
+	manifest.Items = items
+	return results, manifest.write(dir)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/diff"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	RESTORE_CREATE    = "create"
+	RESTORE_UPDATE    = "update"
+	RESTORE_UNCHANGED = "unchanged"
+)
+
+type RestoreAction struct {
+	Item    Item
+	Action  string
+	Current string
+	Config  string
+	// CreateOnly is set on the parent folders of the selected jobs, which are
+	// only restored when they are missing.
+	CreateOnly bool
+	Err        error
+}
+
+type RestorePlan struct {
+	Actions []RestoreAction
+}
+
+// selectItems returns the items matching the only pattern, along with their
+// parent folders.
+func (manifest *Manifest) selectItems(only string) ([]RestoreAction, error) {
+	selected := map[string]bool{}
+	for _, item := range manifest.Items {
+		if only == "" {
+			selected[item.Name] = true
+			continue
+		}
+		match, err := path.Match(only, item.Name)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			selected[item.Name] = true
+		}
+	}
+
+	actions := []RestoreAction{}
+	for _, item := range manifest.Items {
+		if selected[item.Name] {
+			actions = append(actions, RestoreAction{Item: item})
+			continue
+		}
+		for name := range selected {
+			if strings.HasPrefix(name, item.Name+"/") {
+				actions = append(actions, RestoreAction{Item: item, CreateOnly: true})
+				break
+			}
+		}
+	}
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].Item.depth() < actions[j].Item.depth()
+	})
+	return actions, nil
+}
+
+func (action *RestoreAction) plan(clt *apiclient.ApiClient, dir string) error {
+	content, err := ioutil.ReadFile(filepath.Join(dir, action.Item.Path))
+	if err != nil {
+		return err
+	}
+	if checksum(content) != action.Item.Sha256 {
+		return fmt.Errorf("checksum of %s does not match the manifest", action.Item.Path)
+	}
+	action.Config = string(content)
+
+	action.Current, err = jobs.GetJobConfig(clt, action.Item.Name)
+	switch {
+	case err != nil && err.Error() == "404":
+		action.Action = RESTORE_CREATE
+	case err != nil:
+		return err
+	case action.CreateOnly ||
+		normalizeConfig(action.Current) == normalizeConfig(action.Config):
+		action.Action = RESTORE_UNCHANGED
+	default:
+		action.Action = RESTORE_UPDATE
+	}
+	return nil
+}
+
+// PlanRestore compares the jobs saved in dir with the jobs of the Jenkins
+// server, without modifying them.
+func (manifest *Manifest) PlanRestore(
+	clt *apiclient.ApiClient, dir string, only string,
+) (*RestorePlan, error) {
+	actions, err := manifest.selectItems(only)
+	if err != nil {
+		return nil, err
+	}
+	clt.RunConcurrently(len(actions), func(i int) {
+		actions[i].Err = actions[i].plan(clt, dir)
+	})
+	return &RestorePlan{Actions: actions}, nil
+}
+
+// HasChanges reports whether at least one job needs to be created or updated.
+func (plan *RestorePlan) HasChanges() bool {
+	for _, action := range plan.Actions {
+		if action.Err == nil && action.Action != RESTORE_UNCHANGED {
+			return true
+		}
+	}
+	return false
+}
+
+// Failed returns the number of jobs that could not be compared.
+func (plan *RestorePlan) Failed() int {
+	failed := 0
+	for _, action := range plan.Actions {
+		if action.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Diff returns the change of the config.xml of an updated job.
+func (action *RestoreAction) Diff() string {
+	return diff.Unified(
+		action.Item.Name+"/config.xml (current)",
+		action.Item.Name+"/config.xml (backup)",
+		formatConfig(action.Current), formatConfig(action.Config), 3,
+	)
+}
+
+func (action *RestoreAction) apply(clt *apiclient.ApiClient) error {
+	switch action.Action {
+	case RESTORE_CREATE:
+		name, parents := jobs.SplitJobPath(action.Item.Name)
+		_, err := clt.Jenkins.CreateJobInFolder(clt.Ctx, action.Config, name, parents...)
+		return err
+	case RESTORE_UPDATE:
+		return jobs.NewJenkinsJob(clt, action.Item.Name).UpdateConfig(clt.Ctx, action.Config)
+	}
+	return nil
+}
+
+// Apply creates and updates the jobs of the plan and returns the outcome for
+// each of them. The parent folders are restored before their content, so the
+// actions are applied one by one.
+func (plan *RestorePlan) Apply(clt *apiclient.ApiClient) (results []jobs.ActionResult, err error) {
+	names := []string{}
+	defer func() { jobs.RecordAudit(clt, "restore", names, results, err) }()
+
+	results = []jobs.ActionResult{}
+	failed := 0
+	for i := range plan.Actions {
+		action := &plan.Actions[i]
+		if action.Err != nil || action.Action == RESTORE_UNCHANGED {
+			continue
+		}
+		names = append(names, action.Item.Name)
+		result := jobs.ActionResult{Job: action.Item.Name, Status: jobs.ACTION_STATUS_DONE}
+		if err := action.apply(clt); err != nil {
+			action.Err = err
+			failed++
+			result.Status = jobs.ACTION_STATUS_FAILED
+			result.Message = fmt.Sprintf("job %s failed: %s", action.Item.Name, err)
+		} else {
+			result.Message = fmt.Sprintf("job %s is now %sd", action.Item.Name, action.Action)
+		}
+		results = append(results, result)
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d jobs could not be restored", failed)
+	}
+	return results, nil
+}
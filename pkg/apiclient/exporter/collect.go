@@ -0,0 +1,188 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"errors"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// jobLabels returns the labels of a job, folder being the full name of its
+// parent folder, empty at the root of Jenkins.
+func jobLabels(job *jobs.Job) []Label {
+	_, parents := jobs.SplitJobPath(job.Name)
+	return []Label{{"job", job.Name}, {"folder", strings.Join(parents, "/")}}
+}
+
+// collectJobs returns the metrics of the jobs matching the filter, from the
+// job listing.
+func collectJobs(clt *apiclient.ApiClient, filter *jobs.JobsFilterParams, now time.Time) ([]*Metric, error) {
+	var listed jobs.Jobs
+	if err := listed.GetFilteredJobs(clt, filter); err != nil {
+		return nil, err
+	}
+
+	result := newGauge(
+		"jenkins_job_last_build_result",
+		"Status of the job, running while its last build is running and otherwise the result of its last build",
+	)
+	number := newGauge("jenkins_job_last_build_number", "Number of the last build of the job")
+	duration := newGauge(
+		"jenkins_job_last_build_duration_seconds",
+		"Duration of the last build of the job, its current duration while it is running",
+	)
+	timestamp := newGauge("jenkins_job_last_build_timestamp_seconds", "Start time of the last build of the job")
+	age := newGauge("jenkins_job_last_build_age_seconds", "Time elapsed since the start of the last build of the job")
+	running := newGauge("jenkins_job_running", "Whether the last build of the job is running")
+	disabled := newGauge("jenkins_job_disabled", "Whether the job is disabled")
+	total := newGauge("jenkins_jobs", "Number of jobs")
+	totalRunning := newGauge("jenkins_jobs_running", "Number of jobs whose last build is running")
+
+	count, runningCount := 0, 0
+	for i := range listed.Jobs {
+		job := &listed.Jobs[i]
+		count++
+		labels := jobLabels(job)
+		result.add(1, append(labels, Label{"result", job.Status()})...)
+		running.add(boolValue(job.IsRunning), labels...)
+		disabled.add(boolValue(job.Disabled), labels...)
+		if job.IsRunning {
+			runningCount++
+		}
+		if job.Result == jobs.JOB_STATUS_NOBUILD {
+			continue
+		}
+		buildDuration := time.Duration(job.LastBuildDuration) * time.Millisecond
+		if job.IsRunning {
+			buildDuration = now.Sub(job.LastBuildCreationDate)
+		}
+		number.add(float64(job.JenkinsLastBuild.GetBuildNumber()), labels...)
+		duration.add(buildDuration.Seconds(), labels...)
+		timestamp.add(float64(job.LastBuildCreationDate.UnixNano())/float64(time.Second), labels...)
+		age.add(now.Sub(job.LastBuildCreationDate).Seconds(), labels...)
+	}
+	total.add(float64(count))
+	totalRunning.add(float64(runningCount))
+	return []*Metric{result, number, duration, timestamp, age, running, disabled, total, totalRunning}, nil
+}
+
+// collectQueue returns the metrics of the build queue.
+func collectQueue(clt *apiclient.ApiClient) ([]*Metric, error) {
+	var response struct {
+		Items []struct {
+			Blocked bool
+			Stuck   bool
+		}
+	}
+	httpResponse, err := clt.Jenkins.Requester.GetJSON(
+		clt.Ctx, "/queue", &response, map[string]string{"tree": "items[blocked,stuck]"},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if httpResponse.StatusCode != 200 {
+		return nil, errors.New(strconv.Itoa(httpResponse.StatusCode))
+	}
+
+	length := newGauge("jenkins_queue_length", "Number of items waiting in the build queue")
+	blocked := newGauge("jenkins_queue_blocked", "Number of items of the build queue blocked by another build")
+	stuck := newGauge("jenkins_queue_stuck", "Number of items of the build queue waiting for an executor which is not available")
+	blockedCount, stuckCount := 0, 0
+	for _, item := range response.Items {
+		if item.Blocked {
+			blockedCount++
+		}
+		if item.Stuck {
+			stuckCount++
+		}
+	}
+	length.add(float64(len(response.Items)))
+	blocked.add(float64(blockedCount))
+	stuck.add(float64(stuckCount))
+	return []*Metric{length, blocked, stuck}, nil
+}
+
+// collectNodes returns the metrics of the nodes and of their executors.
+func collectNodes(clt *apiclient.ApiClient) ([]*Metric, error) {
+	var response struct {
+		Computer []struct {
+			DisplayName  string
+			Offline      bool
+			NumExecutors int
+			Executors    []struct {
+				Idle bool
+			}
+		}
+	}
+	httpResponse, err := clt.Jenkins.Requester.GetJSON(
+		clt.Ctx, "/computer", &response,
+		map[string]string{"tree": "computer[displayName,offline,numExecutors,executors[idle]]"},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if httpResponse.StatusCode != 200 {
+		return nil, errors.New(strconv.Itoa(httpResponse.StatusCode))
+	}
+
+	online := newGauge("jenkins_node_online", "Whether the node is online")
+	executors := newGauge("jenkins_node_executors", "Number of executors of the node")
+	busy := newGauge("jenkins_node_executors_busy", "Number of executors of the node running a build")
+	for _, computer := range response.Computer {
+		labels := []Label{{"node", computer.DisplayName}}
+		busyCount := 0
+		for _, executor := range computer.Executors {
+			if !executor.Idle {
+				busyCount++
+			}
+		}
+		online.add(boolValue(!computer.Offline), labels...)
+		executors.add(float64(computer.NumExecutors), labels...)
+		busy.add(float64(busyCount), labels...)
+	}
+	return []*Metric{online, executors, busy}, nil
+}
+
+// Collect returns the metrics of the jobs matching the filter, of the build
+// queue and of the nodes.
+func Collect(clt *apiclient.ApiClient, filter *jobs.JobsFilterParams, now time.Time) ([]*Metric, error) {
+	metrics, err := collectJobs(clt, filter, now)
+	if err != nil {
+		return nil, err
+	}
+	queueMetrics, err := collectQueue(clt)
+	if err != nil {
+		return nil, err
+	}
+	nodeMetrics, err := collectNodes(clt)
+	if err != nil {
+		return nil, err
+	}
+	metrics = append(metrics, queueMetrics...)
+	return append(metrics, nodeMetrics...), nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Exporter serves the metrics of Jenkins, collected at most once per cache
+// TTL whatever the number of scrapes so that Jenkins is not overloaded.
+type Exporter struct {
+	clt      *apiclient.ApiClient
+	filter   *jobs.JobsFilterParams
+	cacheTTL time.Duration
+
+	mutex     sync.Mutex
+	collected time.Time
+	body      []byte
+	errors    int
+}
+
+func NewExporter(clt *apiclient.ApiClient, filter *jobs.JobsFilterParams, cacheTTL time.Duration) *Exporter {
+	return &Exporter{clt: clt, filter: filter, cacheTTL: cacheTTL}
+}
+
+// collect collects the metrics, jenkins_up being 0 and the other metrics of
+// Jenkins being omitted when they could not be collected.
+func (exporter *Exporter) collect(now time.Time) []byte {
+	metrics, err := Collect(exporter.clt, exporter.filter, now)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		exporter.errors++
+		metrics = nil
+	}
+	up := newGauge("jenkins_up", "Whether the last collection of the metrics of Jenkins succeeded")
+	up.add(boolValue(err == nil))
+	collectDuration := newGauge(
+		"jenkins_exporter_collect_duration_seconds", "Duration of the last collection of the metrics of Jenkins",
+	)
+	collectDuration.add(time.Since(now).Seconds())
+	collected := newGauge(
+		"jenkins_exporter_last_collect_timestamp_seconds", "Time of the last collection of the metrics of Jenkins",
+	)
+	collected.add(float64(now.UnixNano()) / float64(time.Second))
+	errorsTotal := &Metric{
+		Name: "jenkins_exporter_collect_errors_total",
+		Help: "Number of collections of the metrics of Jenkins which failed",
+		Type: METRIC_TYPE_COUNTER,
+	}
+	errorsTotal.add(float64(exporter.errors))
+	metrics = append(metrics, up, collectDuration, collected, errorsTotal)
+
+	var body bytes.Buffer
+	WriteMetrics(&body, metrics)
+	return body.Bytes()
+}
+
+// Metrics returns the metrics in the Prometheus text exposition format,
+// collecting them again when the cached ones are older than the cache TTL.
+func (exporter *Exporter) Metrics() []byte {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+	now := time.Now()
+	if exporter.body == nil || now.Sub(exporter.collected) >= exporter.cacheTTL {
+		exporter.body = exporter.collect(now)
+		exporter.collected = now
+	}
+	return exporter.body
+}
+
+func (exporter *Exporter) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writer.Write(exporter.Metrics())
+}
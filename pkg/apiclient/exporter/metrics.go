@@ -0,0 +1,98 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	METRIC_TYPE_GAUGE   = "gauge"
+	METRIC_TYPE_COUNTER = "counter"
+)
+
+// Label is a label of a sample, given in the order of the metric labels.
+type Label struct {
+	Name  string
+	Value string
+}
+
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Metric is a metric with its samples, written in the Prometheus text
+// exposition format.
+type Metric struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+func newGauge(name string, help string) *Metric {
+	return &Metric{Name: name, Help: help, Type: METRIC_TYPE_GAUGE}
+}
+
+func (metric *Metric) add(value float64, labels ...Label) {
+	metric.Samples = append(metric.Samples, Sample{Labels: labels, Value: value})
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// WriteMetrics writes the metrics in the Prometheus text exposition format.
+func WriteMetrics(writer io.Writer, metrics []*Metric) error {
+	for _, metric := range metrics {
+		_, err := fmt.Fprintf(
+			writer, "# HELP %s %s\n# TYPE %s %s\n", metric.Name, metric.Help, metric.Name, metric.Type,
+		)
+		if err != nil {
+			return err
+		}
+		for _, sample := range metric.Samples {
+			labels := make([]string, len(sample.Labels))
+			for i, label := range sample.Labels {
+				labels[i] = fmt.Sprintf(`%s="%s"`, label.Name, labelValueReplacer.Replace(label.Value))
+			}
+			line := metric.Name
+			if len(labels) > 0 {
+				line += "{" + strings.Join(labels, ",") + "}"
+			}
+			if _, err := fmt.Fprintf(writer, "%s %s\n", line, formatValue(sample.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -17,21 +17,26 @@ limitations under the License.
 package jobs
 
 import (
-	"os"
-	"strings"
+	"jenkinsctl/pkg/selector"
+	"path"
+	"regexp"
 	"time"
 
 	"github.com/bndr/gojenkins"
-	"github.com/olekukonko/tablewriter"
 )
 
 const (
-	JOB_STATUS_ALL     = "all"
-	JOB_STATUS_RUNNING = "running"
-	JOB_STATUS_SUCCESS = "success"
-	JOB_STATUS_FAILED  = "failure"
-	JOB_STATUS_ABORTED = "aborted"
-	JOB_STATUS_NOBUILD = "no_build"
+	JOB_STATUS_ALL      = "all"
+	JOB_STATUS_RUNNING  = "running"
+	JOB_STATUS_SUCCESS  = "success"
+	JOB_STATUS_UNSTABLE = "unstable"
+	JOB_STATUS_FAILED   = "failure"
+	JOB_STATUS_ABORTED  = "aborted"
+	JOB_STATUS_NOTBUILT = "not_built"
+	JOB_STATUS_NOBUILD  = "no_build"
+	JOB_STATUS_DISABLED = "disabled"
+
+	JOB_COLOR_DISABLED = "disabled"
 )
 
 type Job struct {
@@ -40,6 +45,7 @@ type Job struct {
 	LastBuildDuration     float64
 	LastBuildCreationDate time.Time
 	IsRunning             bool
+	Disabled              bool
 	Success               bool
 	Result                string
 	JenkinsJob            *gojenkins.Job
@@ -51,69 +57,100 @@ type Jobs struct {
 }
 
 type JobsFilterParams struct {
-	Name   string
-	AgeMin int
-	AgeMax int
-	Status string
+	Names    []string
+	Regex    string
+	Exclude  []string
+	AgeMin   time.Duration
+	AgeMax   time.Duration
+	Since    time.Time
+	Until    time.Time
+	Status   string
+	Selector *selector.Selector
+	// Recursive lists the jobs of the folders too, which the patterns and
+	// the regex always do.
+	Recursive bool
 }
 
-func (job *Job) checkJobStatusMatch(status string) bool {
-	switch {
-	case status == JOB_STATUS_ALL:
-		return true
-	case status == JOB_STATUS_RUNNING && job.IsRunning:
-		return true
-	case status == JOB_STATUS_SUCCESS && !job.IsRunning && job.Success:
-		return true
-	case status == JOB_STATUS_FAILED && !job.IsRunning && !job.Success:
-		return true
-	case status == JOB_STATUS_ABORTED && job.Result == gojenkins.STATUS_ABORTED:
+// lastBuildDuration returns the duration of the last build, or its current
+// duration when it is running.
+func (job *Job) lastBuildDuration() time.Duration {
+	if job.IsRunning {
+		return time.Since(job.LastBuildCreationDate)
+	}
+	return time.Duration(job.LastBuildDuration) * time.Millisecond
+}
+
+// lastBuildNumber returns the number of the last build, or 0 for the jobs
+// without build.
+func (job *Job) lastBuildNumber() int64 {
+	if job.JenkinsLastBuild == nil || job.JenkinsLastBuild.Raw == nil {
+		return 0
+	}
+	return job.JenkinsLastBuild.GetBuildNumber()
+}
+
+// checkJobNameMatch reports whether the job name matches one of the glob
+// patterns, every job matches when there is no pattern.
+func (job *Job) checkJobNameMatch(patterns []string) bool {
+	if len(patterns) == 0 {
 		return true
-	default:
-		return false
 	}
+	for _, pattern := range patterns {
+		if match, _ := path.Match(pattern, job.Name); match {
+			return true
+		}
+	}
+	return false
+}
+
+func (job *Job) checkJobRegexMatch(regex *regexp.Regexp) bool {
+	return regex == nil || regex.MatchString(job.Name)
 }
 
-func (job *Job) checkJobMinimumAgeMatch(ageMin int) bool {
+func (job *Job) checkJobExcludeMatch(patterns []string) bool {
+	for _, pattern := range patterns {
+		if match, _ := path.Match(pattern, job.Name); match {
+			return false
+		}
+	}
+	return true
+}
+
+// checkJobMinimumAgeMatch reports whether the last build started at least
+// ageMin before now, the jobs without build being considered as old.
+func (job *Job) checkJobMinimumAgeMatch(ageMin time.Duration, now time.Time) bool {
 	if ageMin == 0 {
 		return true
 	}
-	diff := time.Since(job.LastBuildCreationDate).Minutes()
-	return diff >= float64(ageMin)
+	return now.Sub(job.LastBuildCreationDate) >= ageMin
 }
 
-func (job *Job) checkJobMaximumAgeMatch(ageMax int) bool {
+func (job *Job) checkJobMaximumAgeMatch(ageMax time.Duration, now time.Time) bool {
 	if ageMax == 0 {
 		return true
 	}
-	diff := time.Since(job.LastBuildCreationDate).Minutes()
-	return diff < float64(ageMax)
+	return now.Sub(job.LastBuildCreationDate) < ageMax
 }
 
-func (jobs *Jobs) PrintJobsTable() {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Name", "Status", "Build date"})
-	for _, job := range jobs.Jobs {
-		var jobStatus string
-		if job.IsRunning {
-			jobStatus = "running"
-		} else {
-			jobStatus = strings.ToLower(job.Result)
-		}
+// checkJobSinceMatch and checkJobUntilMatch are the absolute versions of the
+// age checks, on the start date of the last build.
+func (job *Job) checkJobSinceMatch(since time.Time) bool {
+	return since.IsZero() || !job.LastBuildCreationDate.Before(since)
+}
 
-		var jobBuildDateStr string
-		if job.Result == JOB_STATUS_NOBUILD {
-			jobBuildDateStr = ""
-		} else {
-			jobBuildDateStr = job.LastBuildCreationDate.Format("2006-01-02 15:04:05")
-		}
+func (job *Job) checkJobUntilMatch(until time.Time) bool {
+	return until.IsZero() || job.LastBuildCreationDate.Before(until)
+}
 
-		j := []string{
-			job.Name,
-			jobStatus,
-			jobBuildDateStr,
-		}
-		table.Append(j)
+// Names returns the full names of the jobs.
+func (jobs *Jobs) Names() []string {
+	names := make([]string, len(jobs.Jobs))
+	for i := range jobs.Jobs {
+		names[i] = jobs.Jobs[i].Name
 	}
-	table.Render()
+	return names
+}
+
+func (jobs *Jobs) PrintJobsTable() {
+	PrintSummariesTable(jobs.Summaries(), DefaultJobColumns, nil)
 }
@@ -0,0 +1,71 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import "fmt"
+
+const (
+	ACTION_STATUS_DONE    = "done"
+	ACTION_STATUS_SKIPPED = "skipped"
+	ACTION_STATUS_FAILED  = "failed"
+	ACTION_STATUS_PLANNED = "planned"
+)
+
+// The dry run modes of the actions: with DRY_RUN_CLIENT the actions are
+// computed without sending any POST request to Jenkins, and with
+// DRY_RUN_SERVER they are in addition validated against the controller where
+// Jenkins allows it.
+const (
+	DRY_RUN_NONE   = "none"
+	DRY_RUN_CLIENT = "client"
+	DRY_RUN_SERVER = "server"
+)
+
+// ActionResult is the outcome of an action on a job, QueueID being the id of
+// the queue item of the started build. On a dry run, Parameters are the
+// parameters the build would be started with and Diff the change of the
+// config.xml of the job.
+type ActionResult struct {
+	Job        string            `json:"job"`
+	Status     string            `json:"status"`
+	QueueID    int64             `json:"queueId,omitempty"`
+	Message    string            `json:"message"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Diff       string            `json:"diff,omitempty"`
+}
+
+func newActionResult(job *Job, status string, format string, args ...interface{}) ActionResult {
+	return newNamedActionResult(job.Name, status, format, args...)
+}
+
+// newNamedActionResult returns the outcome of an action on the job located at
+// fullName, for the actions run on jobs which are not listed.
+func newNamedActionResult(fullName string, status string, format string, args ...interface{}) ActionResult {
+	return ActionResult{Job: fullName, Status: status, Message: fmt.Sprintf(format, args...)}
+}
+
+// CheckDryRun returns an error when the dry run mode is not accepted.
+func CheckDryRun(dryRun string) error {
+	switch dryRun {
+	case DRY_RUN_NONE, DRY_RUN_CLIENT, DRY_RUN_SERVER:
+		return nil
+	}
+	return invalidError(
+		"%s is not accepted dry run mode (possible values: %s, %s, %s)",
+		dryRun, DRY_RUN_NONE, DRY_RUN_CLIENT, DRY_RUN_SERVER,
+	)
+}
@@ -0,0 +1,349 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"jenkinsctl/pkg/apiclient"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	ARTIFACT_DOWNLOADED = "downloaded"
+	ARTIFACT_RESUMED    = "resumed"
+	ARTIFACT_UP_TO_DATE = "up to date"
+	ARTIFACT_EXISTS     = "exists"
+	ARTIFACT_FAILED     = "failed"
+)
+
+// buildRefs gives the permalinks accepted in place of a build number.
+var buildRefs = map[string]string{
+	"last":           "lastBuild",
+	"lastSuccessful": "lastSuccessfulBuild",
+	"lastStable":     "lastStableBuild",
+	"lastCompleted":  "lastCompletedBuild",
+	"lastFailed":     "lastFailedBuild",
+}
+
+// BuildRefs lists the permalinks accepted by ParseBuildRef.
+var BuildRefs = []string{"last", "lastSuccessful", "lastStable", "lastCompleted", "lastFailed"}
+
+// ParseBuildRef returns the url path element of a build given by its number
+// or by one of BuildRefs, the last build when ref is empty.
+func ParseBuildRef(ref string) (string, error) {
+	if ref == "" {
+		return buildRefs["last"], nil
+	}
+	if permalink, ok := buildRefs[ref]; ok {
+		return permalink, nil
+	}
+	if number, err := strconv.ParseInt(ref, 10, 64); err == nil && number > 0 {
+		return ref, nil
+	}
+	return "", invalidError(
+		"invalid build %s, expected a build number or one of %s", ref, strings.Join(BuildRefs, ", "),
+	)
+}
+
+// BuildArtifact is a file archived by a build, with its MD5 checksum when the
+// build recorded its fingerprint.
+type BuildArtifact struct {
+	FileName     string
+	RelativePath string
+	MD5          string `json:"-"`
+}
+
+// BuildArtifacts are the artifacts of a build.
+type BuildArtifacts struct {
+	Job         string `json:"-"`
+	Number      int64
+	Artifacts   []BuildArtifact
+	Fingerprint []struct {
+		FileName string
+		Hash     string
+	}
+}
+
+// ArtifactDownload is the result of the download of an artifact.
+type ArtifactDownload struct {
+	Artifact BuildArtifact
+	Status   string
+	Size     int64
+	Verified bool
+	Err      error
+}
+
+// GetBuildArtifacts returns the artifacts of a build of the job located at
+// fullName, the build being given as accepted by ParseBuildRef.
+func GetBuildArtifacts(clt *apiclient.ApiClient, fullName string, ref string) (*BuildArtifacts, error) {
+	build, err := ParseBuildRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := &BuildArtifacts{}
+	response, err := clt.Jenkins.Requester.GetJSON(
+		clt.Ctx, JobBase(fullName)+"/"+build, artifacts,
+		map[string]string{"tree": "number,artifacts[fileName,relativePath],fingerprint[fileName,hash]"},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == 404 {
+		return nil, notFoundError("build %s of job %s not found", build, fullName)
+	}
+	if response.StatusCode != 200 {
+		return nil, errors.New(strconv.Itoa(response.StatusCode))
+	}
+	artifacts.Job = fullName
+
+	// The fingerprints are recorded with the path of the file, or its name
+	// for the oldest builds.
+	hashes := map[string]string{}
+	for _, fingerprint := range artifacts.Fingerprint {
+		hashes[fingerprint.FileName] = fingerprint.Hash
+	}
+	for i := range artifacts.Artifacts {
+		artifact := &artifacts.Artifacts[i]
+		if hash, ok := hashes[artifact.RelativePath]; ok {
+			artifact.MD5 = hash
+		} else {
+			artifact.MD5 = hashes[artifact.FileName]
+		}
+	}
+	return artifacts, nil
+}
+
+// Filter returns the artifacts whose path or file name matches one of the
+// glob patterns, every artifact when there is no pattern.
+func (artifacts *BuildArtifacts) Filter(patterns []string) ([]BuildArtifact, error) {
+	if err := checkPatterns(patterns); err != nil {
+		return nil, err
+	}
+	if len(patterns) == 0 {
+		return artifacts.Artifacts, nil
+	}
+	filtered := []BuildArtifact{}
+	for _, artifact := range artifacts.Artifacts {
+		for _, pattern := range patterns {
+			matchPath, _ := path.Match(pattern, artifact.RelativePath)
+			matchName, _ := path.Match(pattern, artifact.FileName)
+			if matchPath || matchName {
+				filtered = append(filtered, artifact)
+				break
+			}
+		}
+	}
+	return filtered, nil
+}
+
+// Download downloads the artifacts into dir, MaxConcurentRequests at a time.
+// An artifact is first written to a .part file, so that an interrupted
+// download is resumed by the next call, and its checksum is verified against
+// the fingerprint of the build when there is one. The artifacts already
+// downloaded are skipped unless overwrite is set.
+func (artifacts *BuildArtifacts) Download(
+	clt *apiclient.ApiClient, selected []BuildArtifact, dir string, overwrite bool,
+) []ArtifactDownload {
+	downloads := make([]ArtifactDownload, len(selected))
+	clt.RunConcurrently(len(selected), func(i int) {
+		downloads[i] = artifacts.download(clt, selected[i], dir, overwrite)
+	})
+	return downloads
+}
+
+func (artifacts *BuildArtifacts) download(
+	clt *apiclient.ApiClient, artifact BuildArtifact, dir string, overwrite bool,
+) ArtifactDownload {
+	result := ArtifactDownload{Artifact: artifact, Status: ARTIFACT_FAILED}
+	target := filepath.Join(dir, filepath.FromSlash(artifact.RelativePath))
+	if relative, err := filepath.Rel(dir, target); err != nil || strings.HasPrefix(relative, "..") {
+		result.Err = fmt.Errorf("invalid artifact path %s", artifact.RelativePath)
+		return result
+	}
+
+	if info, err := os.Stat(target); err == nil && !overwrite {
+		result.Size = info.Size()
+		if artifact.MD5 == "" {
+			result.Status = ARTIFACT_EXISTS
+			return result
+		}
+		hash, err := fileMD5(target)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		if hash == artifact.MD5 {
+			result.Status = ARTIFACT_UP_TO_DATE
+			result.Verified = true
+			return result
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		result.Err = err
+		return result
+	}
+	part := target + ".part"
+	if overwrite {
+		os.Remove(part)
+	}
+	status, err := artifacts.fetch(clt, artifact, part)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if artifact.MD5 != "" {
+		hash, err := fileMD5(part)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		if hash != artifact.MD5 {
+			os.Remove(part)
+			result.Err = errors.New("checksum mismatch")
+			return result
+		}
+		result.Verified = true
+	}
+	if err := os.Rename(part, target); err != nil {
+		result.Err = err
+		return result
+	}
+	if info, err := os.Stat(target); err == nil {
+		result.Size = info.Size()
+	}
+	result.Status = status
+	return result
+}
+
+// fetch writes the artifact into the part file, asking only the missing end
+// of the file when the part file already exists.
+func (artifacts *BuildArtifacts) fetch(
+	clt *apiclient.ApiClient, artifact BuildArtifact, part string,
+) (string, error) {
+	var offset int64
+	if info, err := os.Stat(part); err == nil {
+		offset = info.Size()
+	}
+
+	elements := strings.Split(artifact.RelativePath, "/")
+	for i := range elements {
+		elements[i] = url.PathEscape(elements[i])
+	}
+	href := clt.Jenkins.Requester.Base + JobBase(artifacts.Job) + "/" +
+		strconv.FormatInt(artifacts.Number, 10) + "/artifact/" + strings.Join(elements, "/")
+	request, err := http.NewRequestWithContext(clt.Ctx, "GET", href, nil)
+	if err != nil {
+		return "", err
+	}
+	if auth := clt.Jenkins.Requester.BasicAuth; auth != nil {
+		request.SetBasicAuth(auth.Username, auth.Password)
+	}
+	if offset > 0 {
+		request.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+	response, err := clt.Jenkins.Requester.Client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	status := ARTIFACT_DOWNLOADED
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusPartialContent:
+		status = ARTIFACT_RESUMED
+		flags = os.O_WRONLY | os.O_APPEND
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The part file is already complete.
+		return ARTIFACT_RESUMED, nil
+	default:
+		return "", fmt.Errorf("download failed with status %d", response.StatusCode)
+	}
+	file, err := os.OpenFile(part, flags, 0644)
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(file, response.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return status, err
+}
+
+func fileMD5(name string) (string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func PrintArtifactsTable(artifacts []BuildArtifact) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Path", "MD5"})
+	for _, artifact := range artifacts {
+		table.Append([]string{artifact.RelativePath, artifact.MD5})
+	}
+	table.Render()
+}
+
+// PrintDownloadsTable prints the result of the downloads and returns an
+// error when one of them failed.
+func PrintDownloadsTable(downloads []ArtifactDownload) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Path", "Status", "Size", "Checksum"})
+	failed := 0
+	for _, download := range downloads {
+		status := download.Status
+		if download.Err != nil {
+			status += ": " + download.Err.Error()
+			failed++
+		}
+		size, checksum := "", ""
+		if download.Err == nil {
+			size = strconv.FormatInt(download.Size, 10)
+			checksum = "not available"
+		}
+		if download.Verified {
+			checksum = "verified"
+		}
+		table.Append([]string{download.Artifact.RelativePath, status, size, checksum})
+	}
+	table.Render()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d artifacts not downloaded", failed, len(downloads))
+	}
+	return nil
+}
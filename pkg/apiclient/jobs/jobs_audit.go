@@ -0,0 +1,67 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/audit"
+)
+
+// RecordAudit passes the action run on the jobs located at names to the audit
+// hook of the client, with the outcome for each job and the error which
+// interrupted it, if any. Every action changing the jobs is recorded this
+// way, by the functions running it.
+func RecordAudit(clt *apiclient.ApiClient, action string, names []string, results []ActionResult, err error) {
+	if clt.Audit == nil {
+		return
+	}
+	entry := audit.NewEntry(action, clt.Username(), clt.Address())
+	entry.Jobs = append(entry.Jobs, names...)
+	for _, result := range results {
+		entry.Outcomes = append(entry.Outcomes, audit.Outcome{
+			Job: result.Job, Status: result.Status, Message: result.Message,
+		})
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	clt.Audit(entry)
+}
+
+// recordAudit records the action run on the jobs, unless it was a dry run.
+func (jobs *Jobs) recordAudit(
+	clt *apiclient.ApiClient, action string, dryRun string, results []ActionResult, err error,
+) {
+	if dryRun == DRY_RUN_NONE {
+		RecordAudit(clt, action, jobs.Names(), results, err)
+	}
+}
+
+// recordJobAudit records an action run on the single job located at
+// fullName, unless it was a dry run, the error giving the outcome when the
+// action failed.
+func recordJobAudit(
+	clt *apiclient.ApiClient, action string, fullName string, dryRun string, result ActionResult, err error,
+) {
+	if dryRun != DRY_RUN_NONE {
+		return
+	}
+	if err != nil {
+		result = newNamedActionResult(fullName, ACTION_STATUS_FAILED, "%s", err)
+	}
+	RecordAudit(clt, action, []string{fullName}, []ActionResult{result}, err)
+}
@@ -0,0 +1,278 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bndr/gojenkins"
+	"github.com/olekukonko/tablewriter"
+)
+
+// BuildChange is a commit of the changesets of a build, AffectedPaths being
+// the files it modified.
+type BuildChange struct {
+	CommitID string
+	Msg      string
+	Author   struct {
+		FullName string
+	}
+	AffectedPaths []string
+}
+
+type buildChangeSet struct {
+	Items []BuildChange
+}
+
+// Culprit is an author of the commits built since the last successful build.
+type Culprit struct {
+	Author  string
+	Commits int
+	Builds  []int64
+}
+
+// Culprits are the authors of the commits built between the last successful
+// build of a job, LastSuccess being 0 when there is none, and a failed build.
+type Culprits struct {
+	Build       *BuildDetails
+	LastSuccess int64
+	Builds      []BuildDetails
+	Culprits    []Culprit
+}
+
+const buildChangesTree = "number,result,building," +
+	"changeSet[items[commitId,msg,author[fullName],affectedPaths]]," +
+	"changeSets[items[commitId,msg,author[fullName],affectedPaths]]"
+
+// The builds are read by pages of buildChangesPage builds when looking for
+// the builds since a given one.
+const buildChangesPage = 50
+
+// ShortCommitID returns the first 8 characters of the commit id.
+func (change *BuildChange) ShortCommitID() string {
+	if len(change.CommitID) > 8 {
+		return change.CommitID[:8]
+	}
+	return change.CommitID
+}
+
+// Summary returns the first line of the commit message.
+func (change *BuildChange) Summary() string {
+	summary := strings.TrimSpace(change.Msg)
+	if i := strings.Index(summary, "\n"); i >= 0 {
+		summary = summary[:i]
+	}
+	return summary
+}
+
+func (build *BuildDetails) changes() []BuildChange {
+	changes := append([]BuildChange{}, build.ChangeSet.Items...)
+	for _, changeSet := range build.ChangeSets {
+		changes = append(changes, changeSet.Items...)
+	}
+	return changes
+}
+
+// getBuildsChanges returns the builds of the job from the start-th most
+// recent one to the end-th excluded, with their changesets.
+func getBuildsChanges(clt *apiclient.ApiClient, fullName string, start int, end int) ([]BuildDetails, error) {
+	var response struct {
+		AllBuilds []BuildDetails
+	}
+	_, err := clt.Jenkins.Requester.GetJSON(
+		clt.Ctx, JobBase(fullName), &response,
+		map[string]string{"tree": fmt.Sprintf("allBuilds[%s]{%d,%d}", buildChangesTree, start, end)},
+	)
+	if err != nil {
+		return nil, err
+	}
+	for i := range response.AllBuilds {
+		response.AllBuilds[i].Job = fullName
+	}
+	return response.AllBuilds, nil
+}
+
+// walkBuildsChanges calls walk with the builds of the job, the most recent
+// first, until it returns false or there are no more builds.
+func walkBuildsChanges(clt *apiclient.ApiClient, fullName string, walk func(build *BuildDetails) bool) error {
+	for start := 0; ; start += buildChangesPage {
+		builds, err := getBuildsChanges(clt, fullName, start, start+buildChangesPage)
+		if err != nil {
+			return err
+		}
+		for i := range builds {
+			if !walk(&builds[i]) {
+				return nil
+			}
+		}
+		if len(builds) < buildChangesPage {
+			return nil
+		}
+	}
+}
+
+// GetChanges returns the builds of the job located at fullName with their
+// changesets, the most recent first: the builds after the build number since
+// when it is set, otherwise the build given as accepted by ParseBuildRef.
+func GetChanges(clt *apiclient.ApiClient, fullName string, ref string, since int64) ([]BuildDetails, error) {
+	if since == 0 {
+		build, err := GetBuildDetails(clt, fullName, ref)
+		if err != nil {
+			return nil, err
+		}
+		return []BuildDetails{*build}, nil
+	}
+	builds := []BuildDetails{}
+	err := walkBuildsChanges(clt, fullName, func(build *BuildDetails) bool {
+		if build.Number <= since {
+			return false
+		}
+		builds = append(builds, *build)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return builds, nil
+}
+
+// GetCulprits returns the authors of the commits built by the builds of the
+// job located at fullName since its last successful build, up to the build
+// given as accepted by ParseBuildRef. There are no culprits when this build
+// is successful.
+func GetCulprits(clt *apiclient.ApiClient, fullName string, ref string) (*Culprits, error) {
+	number, err := GetBuildNumber(clt, fullName, ref)
+	if err != nil {
+		return nil, err
+	}
+	culprits := &Culprits{Builds: []BuildDetails{}, Culprits: []Culprit{}}
+	err = walkBuildsChanges(clt, fullName, func(build *BuildDetails) bool {
+		switch {
+		case build.Number > number:
+			return true
+		case build.Number == number:
+			culprits.Build = build
+			return build.Result != gojenkins.STATUS_SUCCESS
+		case build.Result == gojenkins.STATUS_SUCCESS:
+			culprits.LastSuccess = build.Number
+			return false
+		}
+		culprits.Builds = append(culprits.Builds, *build)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if culprits.Build == nil {
+		return nil, notFoundError("build %d of job %s not found", number, fullName)
+	}
+	if culprits.Build.Result == gojenkins.STATUS_SUCCESS {
+		return culprits, nil
+	}
+	culprits.Builds = append([]BuildDetails{*culprits.Build}, culprits.Builds...)
+
+	authors := map[string]*Culprit{}
+	for i := len(culprits.Builds) - 1; i >= 0; i-- {
+		build := &culprits.Builds[i]
+		for _, change := range build.changes() {
+			author, ok := authors[change.Author.FullName]
+			if !ok {
+				author = &Culprit{Author: change.Author.FullName}
+				authors[change.Author.FullName] = author
+			}
+			author.Commits++
+			if len(author.Builds) == 0 || author.Builds[len(author.Builds)-1] != build.Number {
+				author.Builds = append(author.Builds, build.Number)
+			}
+		}
+	}
+	for _, author := range authors {
+		culprits.Culprits = append(culprits.Culprits, *author)
+	}
+	sort.Slice(culprits.Culprits, func(i, j int) bool {
+		if culprits.Culprits[i].Commits != culprits.Culprits[j].Commits {
+			return culprits.Culprits[i].Commits > culprits.Culprits[j].Commits
+		}
+		return culprits.Culprits[i].Author < culprits.Culprits[j].Author
+	})
+	return culprits, nil
+}
+
+func formatBuildNumbers(numbers []int64) string {
+	builds := make([]string, len(numbers))
+	for i, number := range numbers {
+		builds[i] = "#" + strconv.FormatInt(number, 10)
+	}
+	return strings.Join(builds, ", ")
+}
+
+// PrintChangesTable prints the commits of the builds, with the files they
+// modified.
+func PrintChangesTable(builds []BuildDetails) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Build", "Commit", "Author", "Message", "Files"})
+	table.SetAutoWrapText(false)
+	table.SetRowLine(true)
+	commits := 0
+	for i := range builds {
+		build := &builds[i]
+		for _, change := range build.changes() {
+			commits++
+			table.Append([]string{
+				"#" + strconv.FormatInt(build.Number, 10),
+				change.ShortCommitID(),
+				change.Author.FullName,
+				change.Summary(),
+				strings.Join(change.AffectedPaths, "\n"),
+			})
+		}
+	}
+	fmt.Printf("%d commits in %d builds\n", commits, len(builds))
+	if commits > 0 {
+		table.Render()
+	}
+}
+
+func (culprits *Culprits) PrintCulpritsTable() {
+	build := culprits.Build
+	if build.Result == gojenkins.STATUS_SUCCESS {
+		fmt.Printf("%s #%d is successful, there are no culprits\n", build.Job, build.Number)
+		return
+	}
+	since := "no successful build"
+	if culprits.LastSuccess > 0 {
+		since = fmt.Sprintf("the last successful build #%d", culprits.LastSuccess)
+	}
+	fmt.Printf(
+		"%s #%d %s: %d builds since %s\n", build.Job, build.Number, build.result(), len(culprits.Builds), since,
+	)
+	if len(culprits.Culprits) == 0 {
+		fmt.Println("no commits in these builds")
+		return
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Author", "Commits", "Builds"})
+	for _, culprit := range culprits.Culprits {
+		table.Append([]string{culprit.Author, strconv.Itoa(culprit.Commits), formatBuildNumbers(culprit.Builds)})
+	}
+	table.Render()
+}
@@ -0,0 +1,138 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+type jobColumn struct {
+	header string
+	value  func(job *JobSummary) string
+}
+
+var jobColumns = map[string]jobColumn{
+	"name": {"Name", func(job *JobSummary) string {
+		return job.Name
+	}},
+	"status": {"Status", func(job *JobSummary) string {
+		return job.Status
+	}},
+	"date": {"Build date", func(job *JobSummary) string {
+		if job.LastBuildTimestamp == nil {
+			return ""
+		}
+		return job.LastBuildTimestamp.Format("2006-01-02 15:04:05")
+	}},
+	"disabled": {"Disabled", func(job *JobSummary) string {
+		if job.Disabled {
+			return "yes"
+		}
+		return ""
+	}},
+	"duration": {"Duration", func(job *JobSummary) string {
+		if job.LastBuildTimestamp == nil {
+			return ""
+		}
+		return time.Duration(job.LastBuildDuration * float64(time.Second)).Round(time.Second).String()
+	}},
+	"number": {"Build number", func(job *JobSummary) string {
+		if job.LastBuildTimestamp == nil {
+			return ""
+		}
+		return strconv.FormatInt(job.LastBuildNumber, 10)
+	}},
+	"url": {"Url", func(job *JobSummary) string {
+		if job.LastBuildUrl != "" {
+			return job.LastBuildUrl
+		}
+		return job.Url
+	}},
+	"node": {"Node", func(job *JobSummary) string {
+		return job.LastBuildNode
+	}},
+	"cause": {"Cause", func(job *JobSummary) string {
+		return job.LastBuildCause
+	}},
+}
+
+// JobColumns lists the columns of the jobs table, the url is the one of the
+// last build, or of the job when it has no build.
+var JobColumns = []string{"name", "status", "date", "disabled", "duration", "number", "url", "node", "cause"}
+
+var DefaultJobColumns = []string{"name", "status", "date", "disabled"}
+
+func CheckColumns(columns []string) error {
+	for _, column := range columns {
+		if _, ok := jobColumns[column]; !ok {
+			return invalidError(
+				"%s is not accepted column (possible values: %s)",
+				column, strings.Join(JobColumns, ", "),
+			)
+		}
+	}
+	return nil
+}
+
+// ColumnValue returns the value printed for the job in one of JobColumns.
+func (job *JobSummary) ColumnValue(column string) string {
+	if jobColumn, ok := jobColumns[column]; ok {
+		return jobColumn.value(job)
+	}
+	return ""
+}
+
+// ColumnValue returns the value printed for the job in one of JobColumns.
+func (job *Job) ColumnValue(column string) string {
+	summary := job.Summary()
+	return summary.ColumnValue(column)
+}
+
+// PrintSummariesTable prints the jobs with the given columns, which must have
+// been checked with CheckColumns, the rows of the jobs whose status changed
+// being printed in bold yellow.
+func PrintSummariesTable(summaries []JobSummary, columns []string, changes []JobStatusChange) {
+	highlighted := map[string]bool{}
+	for _, change := range changes {
+		highlighted[change.Name] = true
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	headers := make([]string, len(columns))
+	highlight := make([]tablewriter.Colors, len(columns))
+	for i, column := range columns {
+		headers[i] = jobColumns[column].header
+		highlight[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgYellowColor}
+	}
+	table.SetHeader(headers)
+	for i := range summaries {
+		row := make([]string, len(columns))
+		for j, column := range columns {
+			row[j] = jobColumns[column].value(&summaries[i])
+		}
+		if highlighted[summaries[i].Name] {
+			table.Rich(row, highlight)
+		} else {
+			table.Append(row)
+		}
+	}
+	table.Render()
+}
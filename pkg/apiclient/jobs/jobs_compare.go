@@ -0,0 +1,284 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/diff"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BuildDetails is a build with the information compared by CompareBuilds.
+type BuildDetails struct {
+	Job       string `json:"-"`
+	Number    int64
+	Result    string
+	Building  bool
+	Timestamp int64
+	Duration  int64
+	BuiltOn   string
+	Actions   []struct {
+		Parameters []struct {
+			Name  string
+			Value interface{}
+		}
+		Causes []struct {
+			ShortDescription string
+		}
+		LastBuiltRevision *struct {
+			SHA1 string
+		}
+		RemoteUrls []string
+	}
+	ChangeSet  buildChangeSet
+	ChangeSets []buildChangeSet
+}
+
+// BuildComparison gives the differences between two builds of a job, the
+// slices and maps only holding what differs.
+type BuildComparison struct {
+	A             *BuildDetails
+	B             *BuildDetails
+	Fields        [][3]string
+	ChangesA      []BuildChange
+	ChangesB      []BuildChange
+	NewlyFailing  []string
+	Fixed         []string
+	TestsCompared bool
+	LogDiff       string
+}
+
+// Timestamps are removed from the console logs before the comparison, either
+// at the start of the lines (timestamper plugin) or inside them.
+var (
+	leadingTimestampRegex = regexp.MustCompile(
+		`^\[?(\d{4}-\d{2}-\d{2}[T ])?\d{2}:\d{2}:\d{2}([.,]\d+)?(Z|[+-]\d{2}:?\d{2})?\]?\s*`,
+	)
+	timestampRegex = regexp.MustCompile(
+		`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}([.,]\d+)?(Z|[+-]\d{2}:?\d{2})?`,
+	)
+)
+
+// GetBuildDetails returns a build of the job located at fullName, the build
+// being given as accepted by ParseBuildRef.
+func GetBuildDetails(clt *apiclient.ApiClient, fullName string, ref string) (*BuildDetails, error) {
+	build, err := ParseBuildRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	details := &BuildDetails{}
+	response, err := clt.Jenkins.Requester.GetJSON(clt.Ctx, JobBase(fullName)+"/"+build, details, nil)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == 404 {
+		return nil, notFoundError("build %s of job %s not found", build, fullName)
+	}
+	if response.StatusCode != 200 {
+		return nil, errors.New(strconv.Itoa(response.StatusCode))
+	}
+	details.Job = fullName
+	return details, nil
+}
+
+// result returns the result of the build, or running while it is not over.
+func (build *BuildDetails) result() string {
+	if build.Building {
+		return JOB_STATUS_RUNNING
+	}
+	return build.Result
+}
+
+func (build *BuildDetails) causes() string {
+	causes := []string{}
+	for _, action := range build.Actions {
+		for _, cause := range action.Causes {
+			causes = append(causes, cause.ShortDescription)
+		}
+	}
+	return strings.Join(causes, ", ")
+}
+
+func (build *BuildDetails) parameters() map[string]string {
+	parameters := map[string]string{}
+	for _, action := range build.Actions {
+		for _, parameter := range action.Parameters {
+			parameters[parameter.Name] = fmt.Sprint(parameter.Value)
+		}
+	}
+	return parameters
+}
+
+// revisions returns the revision built for each SCM remote url.
+func (build *BuildDetails) revisions() map[string]string {
+	revisions := map[string]string{}
+	for _, action := range build.Actions {
+		if action.LastBuiltRevision == nil {
+			continue
+		}
+		remote := strings.Join(action.RemoteUrls, ", ")
+		revisions[remote] = action.LastBuiltRevision.SHA1
+	}
+	return revisions
+}
+
+func (build *BuildDetails) consoleText(clt *apiclient.ApiClient) (string, error) {
+	var text string
+	response, err := clt.Jenkins.Requester.Get(
+		clt.Ctx, JobBase(build.Job)+"/"+strconv.FormatInt(build.Number, 10)+"/consoleText", &text, nil,
+	)
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode != 200 {
+		return "", errors.New(strconv.Itoa(response.StatusCode))
+	}
+	return text, nil
+}
+
+// normalizeLog removes the timestamps of the log and keeps its last lines.
+func normalizeLog(text string, lines int) string {
+	logLines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if lines > 0 && len(logLines) > lines {
+		logLines = logLines[len(logLines)-lines:]
+	}
+	for i, line := range logLines {
+		line = strings.TrimSuffix(line, "\r")
+		line = leadingTimestampRegex.ReplaceAllString(line, "")
+		logLines[i] = timestampRegex.ReplaceAllString(line, "<timestamp>")
+	}
+	return strings.Join(logLines, "\n") + "\n"
+}
+
+// failedTests returns the names of the failed tests of the build, and false
+// when the build has no test report.
+func (build *BuildDetails) failedTests(clt *apiclient.ApiClient) (map[string]bool, bool, error) {
+	report, err := GetTestReport(clt, build.Job, build.Number)
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	failed := map[string]bool{}
+	for _, test := range report.FailedTests() {
+		failed[test.FullName()] = true
+	}
+	return failed, true, nil
+}
+
+func formatBuildDuration(millis int64) string {
+	return (time.Duration(millis) * time.Millisecond).Round(time.Second).String()
+}
+
+// addField adds the values of a field to the comparison when they differ, or
+// in any case when always is set.
+func (comparison *BuildComparison) addField(name string, a string, b string, always bool) {
+	if a != b || always {
+		comparison.Fields = append(comparison.Fields, [3]string{name, a, b})
+	}
+}
+
+// addMapFields adds the entries of the maps whose values differ.
+func (comparison *BuildComparison) addMapFields(prefix string, a map[string]string, b map[string]string) {
+	keys := []string{}
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		comparison.addField(prefix+" "+key, a[key], b[key], false)
+	}
+}
+
+// CompareBuilds compares two builds of the job located at fullName. The
+// console logs are compared on their last logLines lines, 0 meaning the whole
+// logs, and are not compared when logLines is negative.
+func CompareBuilds(
+	clt *apiclient.ApiClient, fullName string, refA string, refB string, logLines int, context int,
+) (*BuildComparison, error) {
+	builds := make([]*BuildDetails, 2)
+	for i, ref := range []string{refA, refB} {
+		build, err := GetBuildDetails(clt, fullName, ref)
+		if err != nil {
+			return nil, err
+		}
+		builds[i] = build
+	}
+	a, b := builds[0], builds[1]
+	comparison := &BuildComparison{A: a, B: b}
+
+	comparison.addField("result", a.result(), b.result(), true)
+	comparison.addField("duration", formatBuildDuration(a.Duration), formatBuildDuration(b.Duration), true)
+	comparison.addField("node", a.BuiltOn, b.BuiltOn, false)
+	comparison.addField("causes", a.causes(), b.causes(), false)
+	comparison.addMapFields("parameter", a.parameters(), b.parameters())
+	comparison.addMapFields("revision", a.revisions(), b.revisions())
+	comparison.ChangesA = a.changes()
+	comparison.ChangesB = b.changes()
+
+	failedA, reportA, err := a.failedTests(clt)
+	if err != nil {
+		return nil, err
+	}
+	failedB, reportB, err := b.failedTests(clt)
+	if err != nil {
+		return nil, err
+	}
+	if reportA && reportB {
+		comparison.TestsCompared = true
+		for name := range failedB {
+			if !failedA[name] {
+				comparison.NewlyFailing = append(comparison.NewlyFailing, name)
+			}
+		}
+		for name := range failedA {
+			if !failedB[name] {
+				comparison.Fixed = append(comparison.Fixed, name)
+			}
+		}
+		sort.Strings(comparison.NewlyFailing)
+		sort.Strings(comparison.Fixed)
+	}
+
+	if logLines >= 0 {
+		logs := make([]string, 2)
+		for i, build := range builds {
+			text, err := build.consoleText(clt)
+			if err != nil {
+				return nil, err
+			}
+			logs[i] = normalizeLog(text, logLines)
+		}
+		comparison.LogDiff = diff.Unified(
+			fmt.Sprintf("#%d/consoleText", a.Number), fmt.Sprintf("#%d/consoleText", b.Number),
+			logs[0], logs[1], context,
+		)
+	}
+	return comparison, nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"regexp"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+var xmlHeaderRegex = regexp.MustCompile(`^<\?xml.*\?>`)
+
+// simplePathRegex matches the paths made only of element names, which can be
+// created when no element matches them.
+var simplePathRegex = regexp.MustCompile(`^/?[\w.-]+(/[\w.-]+)*$`)
+
+type ConfigEdit struct {
+	XPath  string
+	Value  string
+	Delete bool
+}
+
+// JobConfigChange is the edit of the configuration of a job. Before and After
+// are the canonical forms of the current and the new configuration, compared
+// and shown in the diffs, while Config is the new configuration sent to
+// Jenkins, which keeps the formatting of the current one.
+type JobConfigChange struct {
+	Job    Job
+	Before string
+	After  string
+	Config string
+	Err    error
+}
+
+type JobConfigChanges struct {
+	Edit    *ConfigEdit
+	Changes []JobConfigChange
+}
+
+func parseConfig(rawXml string) (*etree.Document, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(xmlHeaderRegex.ReplaceAllString(rawXml, "")); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (job *Job) readConfig(clt *apiclient.ApiClient) (*etree.Document, error) {
+	rawXml, err := job.JenkinsJob.GetConfig(clt.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseConfig(rawXml)
+	if err != nil {
+		return nil, err
+	}
+	doc.Indent(2)
+	return doc, nil
+}
+
+// splitXPath splits an expression ending with "/@name" into the path of the
+// elements and the name of the attribute.
+func splitXPath(xpath string) (string, string) {
+	i := strings.LastIndex(xpath, "/@")
+	if i < 0 || strings.ContainsAny(xpath[i+2:], "/[]") {
+		return xpath, ""
+	}
+	return xpath[:i], xpath[i+2:]
+}
+
+func createElementPath(doc *etree.Document, elementPath string) (*etree.Element, error) {
+	if !simplePathRegex.MatchString(elementPath) {
+		return nil, fmt.Errorf("no element matches %s", elementPath)
+	}
+	names := strings.Split(strings.TrimPrefix(elementPath, "/"), "/")
+	element := doc.Root()
+	if element == nil || element.Tag != names[0] {
+		return nil, fmt.Errorf("no element matches %s", elementPath)
+	}
+	for _, name := range names[1:] {
+		element = selectOrCreateElement(element, name)
+	}
+	return element, nil
+}
+
+func (edit *ConfigEdit) apply(doc *etree.Document) error {
+	elementPath, attr := splitXPath(edit.XPath)
+	path, err := etree.CompilePath(elementPath)
+	if err != nil {
+		return fmt.Errorf("invalid xpath %s: %s", edit.XPath, err)
+	}
+	elements := doc.FindElementsPath(path)
+
+	if edit.Delete {
+		for _, element := range elements {
+			if attr != "" {
+				element.RemoveAttr(attr)
+			} else if element.Parent() != nil {
+				element.Parent().RemoveChild(element)
+			}
+		}
+		return nil
+	}
+
+	if len(elements) == 0 {
+		element, err := createElementPath(doc, elementPath)
+		if err != nil {
+			return err
+		}
+		elements = []*etree.Element{element}
+	}
+	for _, element := range elements {
+		if attr != "" {
+			element.CreateAttr(attr, edit.Value)
+		} else {
+			element.SetText(edit.Value)
+		}
+	}
+	return nil
+}
+
+// prepareConfigEdit applies the edit to the configuration of the job without
+// reindenting it, so that only the edited elements change on Jenkins.
+func (job *Job) prepareConfigEdit(clt *apiclient.ApiClient, edit *ConfigEdit) JobConfigChange {
+	change := JobConfigChange{Job: *job}
+	rawXml, err := job.JenkinsJob.GetConfig(clt.Ctx)
+	if err != nil {
+		change.Err = err
+		return change
+	}
+	doc, err := parseConfig(rawXml)
+	if err != nil {
+		change.Err = err
+		return change
+	}
+	current, err := doc.WriteToString()
+	if err != nil {
+		change.Err = err
+		return change
+	}
+	if err = edit.apply(doc); err != nil {
+		change.Err = err
+		return change
+	}
+	if change.Config, err = doc.WriteToString(); err != nil {
+		change.Err = err
+		return change
+	}
+	if change.Before, err = CanonicalizeConfig(current, false); err != nil {
+		change.Err = err
+		return change
+	}
+	change.After, change.Err = CanonicalizeConfig(change.Config, false)
+	return change
+}
+
+// PrepareConfigEdit computes the new configuration of every job without
+// updating them on Jenkins.
+func (jobs *Jobs) PrepareConfigEdit(
+	clt *apiclient.ApiClient, edit *ConfigEdit,
+) *JobConfigChanges {
+	changes := &JobConfigChanges{Edit: edit, Changes: make([]JobConfigChange, len(jobs.Jobs))}
+	clt.RunConcurrently(len(jobs.Jobs), func(i int) {
+		changes.Changes[i] = jobs.Jobs[i].prepareConfigEdit(clt, edit)
+	})
+	return changes
+}
+
+func (change *JobConfigChange) IsChanged() bool {
+	return change.Err == nil && change.Before != change.After
+}
+
+// ChangedJobs returns the jobs whose configuration will be modified.
+func (changes *JobConfigChanges) ChangedJobs() Jobs {
+	jobs := Jobs{}
+	for _, change := range changes.Changes {
+		if change.IsChanged() {
+			jobs.Jobs = append(jobs.Jobs, change.Job)
+		}
+	}
+	return jobs
+}
+
+// Apply updates the configuration of the changed jobs on Jenkins, the error
+// of each job being set in its change.
+func (changes *JobConfigChanges) Apply(clt *apiclient.ApiClient) {
+	applied := make([]bool, len(changes.Changes))
+	clt.RunConcurrently(len(changes.Changes), func(i int) {
+		change := &changes.Changes[i]
+		if !change.IsChanged() {
+			return
+		}
+		applied[i] = true
+		change.Err = change.Job.JenkinsJob.UpdateConfig(clt.Ctx, change.Config)
+	})
+
+	action := "config-set"
+	if changes.Edit.Delete {
+		action = "config-delete"
+	}
+	names := []string{}
+	results := []ActionResult{}
+	for i := range changes.Changes {
+		change := &changes.Changes[i]
+		if !applied[i] {
+			continue
+		}
+		names = append(names, change.Job.Name)
+		if change.Err != nil {
+			results = append(results, newActionResult(
+				&change.Job, ACTION_STATUS_FAILED, "job %s failed: %s", change.Job.Name, change.Err,
+			))
+		} else {
+			results = append(results, newActionResult(
+				&change.Job, ACTION_STATUS_DONE, "job %s configuration is now updated", change.Job.Name,
+			))
+		}
+	}
+	RecordAudit(clt, action, names, results, nil)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"io/ioutil"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/diff"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+// ConfigSource is the location of a config.xml: either a job of a Jenkins
+// server or a local file.
+type ConfigSource struct {
+	Client *apiclient.ApiClient
+	Job    string
+	File   string
+	// Label identifies the Jenkins server of the job in the diff header.
+	Label string
+}
+
+func (source *ConfigSource) name() string {
+	if source.File != "" {
+		return source.File
+	}
+	if source.Label != "" {
+		return source.Label + ":" + source.Job + "/config.xml"
+	}
+	return source.Job + "/config.xml"
+}
+
+func (source *ConfigSource) read() (string, error) {
+	if source.File != "" {
+		content, err := ioutil.ReadFile(source.File)
+		return string(content), err
+	}
+	config, err := GetJobConfig(source.Client, source.Job)
+	if err != nil && err.Error() == "404" {
+		return "", notFoundError("job %s not found", source.Job)
+	}
+	return config, err
+}
+
+// canonicalizeElement sorts the attributes of the element and its children,
+// and removes the version of the plugins when ignorePluginVersions is set.
+func canonicalizeElement(element *etree.Element, ignorePluginVersions bool) {
+	if ignorePluginVersions {
+		if plugin := element.SelectAttr("plugin"); plugin != nil {
+			plugin.Value = strings.SplitN(plugin.Value, "@", 2)[0]
+		}
+	}
+	element.SortAttrs()
+	for _, child := range element.ChildElements() {
+		canonicalizeElement(child, ignorePluginVersions)
+	}
+}
+
+// CanonicalizeConfig rewrites a config.xml so that two semantically equal
+// configurations have the same text: the XML header is removed, attributes
+// are sorted and elements are indented.
+func CanonicalizeConfig(config string, ignorePluginVersions bool) (string, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(xmlHeaderRegex.ReplaceAllString(strings.TrimSpace(config), "")); err != nil {
+		return "", err
+	}
+	if root := doc.Root(); root != nil {
+		canonicalizeElement(root, ignorePluginVersions)
+	}
+	doc.Indent(2)
+	return doc.WriteToString()
+}
+
+// DiffConfigs returns the unified diff between the canonical forms of two
+// configurations, or an empty string when they are semantically equal.
+func DiffConfigs(
+	from *ConfigSource, to *ConfigSource, ignorePluginVersions bool, context int,
+) (string, error) {
+	texts := []string{}
+	for _, source := range []*ConfigSource{from, to} {
+		config, err := source.read()
+		if err != nil {
+			return "", err
+		}
+		canonical, err := CanonicalizeConfig(config, ignorePluginVersions)
+		if err != nil {
+			return "", err
+		}
+		texts = append(texts, canonical)
+	}
+	return diff.Unified(from.name(), to.name(), texts[0], texts[1], context), nil
+}
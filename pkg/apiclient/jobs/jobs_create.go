@@ -0,0 +1,180 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"jenkinsctl/pkg/apiclient"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// parentBase returns the url path of the folder containing a job, or an
+// empty string for the jobs at the root of Jenkins.
+func parentBase(fullName string) string {
+	_, parents := SplitJobPath(fullName)
+	if len(parents) == 0 {
+		return ""
+	}
+	return JobBase(strings.Join(parents, "/"))
+}
+
+// checkJobExists returns an error when the job located at fullName does not
+// exist.
+func checkJobExists(clt *apiclient.ApiClient, fullName string) error {
+	job := Job{}
+	return job.getJobByName(clt, fullName)
+}
+
+// checkJobMissing returns an error when the job located at fullName exists,
+// or when its folder does not.
+func checkJobMissing(clt *apiclient.ApiClient, fullName string) error {
+	_, parents := SplitJobPath(fullName)
+	if len(parents) > 0 {
+		if err := checkJobExists(clt, strings.Join(parents, "/")); err != nil {
+			return err
+		}
+	}
+	response, err := clt.Jenkins.Requester.GetJSON(clt.Ctx, JobBase(fullName), &struct{}{}, nil)
+	if err != nil {
+		return err
+	}
+	switch response.StatusCode {
+	case 404:
+		return nil
+	case 200:
+		return fmt.Errorf("job %s already exists", fullName)
+	}
+	return errors.New(strconv.Itoa(response.StatusCode))
+}
+
+// checkWellFormed returns an error when the XML document is not well-formed,
+// its header being ignored as Jenkins writes XML 1.1 headers.
+func checkWellFormed(document string) error {
+	document = xmlHeaderRegex.ReplaceAllString(strings.TrimSpace(document), "")
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Create creates the job located at fullName from its config.xml and returns
+// the outcome. On a dry run, the job is not created and only the
+// configuration and the name are checked.
+func Create(clt *apiclient.ApiClient, fullName string, config string, dryRun string) (result ActionResult, err error) {
+	defer func() { recordJobAudit(clt, "create", fullName, dryRun, result, err) }()
+	if dryRun != DRY_RUN_NONE {
+		if err := checkWellFormed(config); err != nil {
+			return ActionResult{}, fmt.Errorf("invalid config.xml: %s", err)
+		}
+		if err := checkJobMissing(clt, fullName); err != nil {
+			return ActionResult{}, err
+		}
+		return newNamedActionResult(
+			fullName, ACTION_STATUS_PLANNED, "job %s would be created", fullName,
+		), nil
+	}
+	name, parents := SplitJobPath(fullName)
+	_, err = clt.Jenkins.CreateJobInFolder(clt.Ctx, config, name, parents...)
+	if err != nil {
+		return ActionResult{}, err
+	}
+	return newNamedActionResult(
+		fullName, ACTION_STATUS_DONE, "job %s is now created", fullName,
+	), nil
+}
+
+// Copy creates the job dst with the configuration of the job src and returns
+// the outcome. On a dry run, the job is not copied.
+func Copy(clt *apiclient.ApiClient, src string, dst string, dryRun string) (result ActionResult, err error) {
+	defer func() { recordJobAudit(clt, "copy", dst, dryRun, result, err) }()
+	if err := checkJobExists(clt, src); err != nil {
+		return ActionResult{}, err
+	}
+	if dryRun != DRY_RUN_NONE {
+		if err := checkJobMissing(clt, dst); err != nil {
+			return ActionResult{}, err
+		}
+		return newNamedActionResult(
+			dst, ACTION_STATUS_PLANNED, "job %s would be copied to %s", src, dst,
+		), nil
+	}
+	name, _ := SplitJobPath(dst)
+	querystring := map[string]string{
+		"name": name,
+		"mode": "copy",
+		"from": "/" + strings.Trim(src, "/"),
+	}
+	response, err := clt.Jenkins.Requester.Post(
+		clt.Ctx, parentBase(dst)+"/createItem", nil, nil, querystring,
+	)
+	if err != nil {
+		return ActionResult{}, err
+	}
+	if response.StatusCode != 200 {
+		return ActionResult{}, errors.New(strconv.Itoa(response.StatusCode))
+	}
+	return newNamedActionResult(
+		dst, ACTION_STATUS_DONE, "job %s is now copied to %s", src, dst,
+	), nil
+}
+
+// Rename changes the name of a job and returns the outcome, it cannot move the
+// job to another folder. On a dry run, the job is not renamed.
+func Rename(clt *apiclient.ApiClient, fullName string, newName string, dryRun string) (result ActionResult, err error) {
+	defer func() { recordJobAudit(clt, "rename", fullName, dryRun, result, err) }()
+	if strings.Contains(newName, "/") {
+		return ActionResult{}, errors.New("the new name must not contain a folder")
+	}
+	if err := checkJobExists(clt, fullName); err != nil {
+		return ActionResult{}, err
+	}
+	if dryRun != DRY_RUN_NONE {
+		_, parents := SplitJobPath(fullName)
+		if err := checkJobMissing(clt, strings.Join(append(parents, newName), "/")); err != nil {
+			return ActionResult{}, err
+		}
+		return newNamedActionResult(
+			fullName, ACTION_STATUS_PLANNED, "job %s would be renamed to %s", fullName, newName,
+		), nil
+	}
+	data := url.Values{}
+	data.Set("newName", newName)
+	response, err := clt.Jenkins.Requester.Post(
+		clt.Ctx, JobBase(fullName)+"/doRename", bytes.NewBufferString(data.Encode()), nil, nil,
+	)
+	if err != nil {
+		return ActionResult{}, err
+	}
+	if response.StatusCode != 200 {
+		return ActionResult{}, errors.New(strconv.Itoa(response.StatusCode))
+	}
+	return newNamedActionResult(
+		fullName, ACTION_STATUS_DONE, "job %s is now renamed to %s", fullName, newName,
+	), nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import "jenkinsctl/pkg/apiclient"
+
+// RunningJobs returns the names of the jobs having a running build.
+func (jobs *Jobs) RunningJobs() []string {
+	names := []string{}
+	for _, job := range jobs.Jobs {
+		if job.IsRunning {
+			names = append(names, job.Name)
+		}
+	}
+	return names
+}
+
+// DeleteJobs deletes the jobs, and returns the outcome for each job until an
+// error occurs. The folders are skipped, deleting them deleting all their
+// jobs. On a dry run, the jobs are not deleted, the server dry run checking
+// that they still exist on the controller.
+func (jobs *Jobs) DeleteJobs(clt *apiclient.ApiClient, dryRun string) (results []ActionResult, err error) {
+	defer func() { jobs.recordAudit(clt, "delete", dryRun, results, err) }()
+	results = []ActionResult{}
+	for i := range jobs.Jobs {
+		job := &jobs.Jobs[i]
+		if job.IsFolder() {
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_SKIPPED, "job %s is a folder, its jobs are not deleted", job.Name,
+			))
+			continue
+		}
+		if dryRun != DRY_RUN_NONE {
+			result, err := job.planDelete(clt, dryRun)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
+			continue
+		}
+		isDeleted, err := job.JenkinsJob.Delete(clt.Ctx)
+		if err != nil {
+			return results, err
+		}
+		if isDeleted {
+			results = append(results, newActionResult(job, ACTION_STATUS_DONE, "job %s is now deleted", job.Name))
+		} else {
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_FAILED, "job %s could not be deleted", job.Name,
+			))
+		}
+	}
+	return results, nil
+}
+
+func (job *Job) planDelete(clt *apiclient.ApiClient, dryRun string) (ActionResult, error) {
+	if dryRun == DRY_RUN_SERVER {
+		status, err := job.JenkinsJob.Poll(clt.Ctx)
+		if err != nil {
+			return ActionResult{}, err
+		}
+		if status == 404 {
+			return newActionResult(
+				job, ACTION_STATUS_FAILED, "job %s no longer exists on the controller", job.Name,
+			), nil
+		}
+	}
+	return newActionResult(job, ACTION_STATUS_PLANNED, "job %s would be deleted", job.Name), nil
+}
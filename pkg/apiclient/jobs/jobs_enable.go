@@ -0,0 +1,177 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DISABLE_REASON_PREFIX starts the line added to the description of the jobs
+// disabled with a reason, so that it can be removed when they are enabled.
+const DISABLE_REASON_PREFIX = "Disabled by jenkinsctl: "
+
+// descriptionWithoutReason removes the disable reason from a job description.
+func descriptionWithoutReason(description string) string {
+	lines := []string{}
+	for _, line := range strings.Split(description, "\n") {
+		if !strings.HasPrefix(line, DISABLE_REASON_PREFIX) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+func (job *Job) setDescription(clt *apiclient.ApiClient, description string) error {
+	data := url.Values{}
+	data.Set("description", description)
+	response, err := clt.Jenkins.Requester.Post(
+		clt.Ctx, job.JenkinsJob.Base+"/submitDescription",
+		bytes.NewBufferString(data.Encode()), nil, nil,
+	)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != 200 {
+		return errors.New(strconv.Itoa(response.StatusCode))
+	}
+	return nil
+}
+
+// DisableJobs disables the jobs, adding the reason to their description when
+// it is not empty, and returns the outcome for each job until an error occurs.
+// On a dry run, the jobs are not disabled.
+func (jobs *Jobs) DisableJobs(clt *apiclient.ApiClient, reason string, dryRun string) (results []ActionResult, err error) {
+	defer func() { jobs.recordAudit(clt, "disable", dryRun, results, err) }()
+	results = []ActionResult{}
+	for i := range jobs.Jobs {
+		job := &jobs.Jobs[i]
+		if job.Disabled {
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_SKIPPED, "job %s is already in disabled state", job.Name,
+			))
+			continue
+		}
+		if dryRun != DRY_RUN_NONE {
+			result, err := job.planStateChange(clt, "disabled", dryRun)
+			if err != nil {
+				return results, err
+			}
+			if reason != "" && result.Status == ACTION_STATUS_PLANNED {
+				result.Message += fmt.Sprintf(", with the reason %q in its description", reason)
+			}
+			results = append(results, result)
+			continue
+		}
+		isDisabled, err := job.JenkinsJob.Disable(clt.Ctx)
+		if err != nil {
+			return results, err
+		}
+		if reason != "" {
+			description := descriptionWithoutReason(job.JenkinsJob.GetDescription())
+			if description != "" {
+				description += "\n"
+			}
+			description += fmt.Sprintf(
+				"%s%s (%s)", DISABLE_REASON_PREFIX, reason,
+				time.Now().Format("2006-01-02 15:04:05"),
+			)
+			if err := job.setDescription(clt, description); err != nil {
+				return results, err
+			}
+		}
+		if isDisabled {
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_DONE, "job %s is now in disabled state", job.Name,
+			))
+		} else {
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_FAILED, "job %s could not be disabled", job.Name,
+			))
+		}
+	}
+	return results, nil
+}
+
+// EnableJobs enables the jobs, removing the disable reason from their
+// description, and returns the outcome for each job until an error occurs.
+// On a dry run, the jobs are not enabled.
+func (jobs *Jobs) EnableJobs(clt *apiclient.ApiClient, dryRun string) (results []ActionResult, err error) {
+	defer func() { jobs.recordAudit(clt, "enable", dryRun, results, err) }()
+	results = []ActionResult{}
+	for i := range jobs.Jobs {
+		job := &jobs.Jobs[i]
+		if !job.Disabled {
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_SKIPPED, "job %s is already in enabled state", job.Name,
+			))
+			continue
+		}
+		if dryRun != DRY_RUN_NONE {
+			result, err := job.planStateChange(clt, "enabled", dryRun)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
+			continue
+		}
+		isEnabled, err := job.JenkinsJob.Enable(clt.Ctx)
+		if err != nil {
+			return results, err
+		}
+		description := job.JenkinsJob.GetDescription()
+		if strings.Contains(description, DISABLE_REASON_PREFIX) {
+			if err := job.setDescription(clt, descriptionWithoutReason(description)); err != nil {
+				return results, err
+			}
+		}
+		if isEnabled {
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_DONE, "job %s is now in enabled state", job.Name,
+			))
+		} else {
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_FAILED, "job %s could not be enabled", job.Name,
+			))
+		}
+	}
+	return results, nil
+}
+
+// planStateChange returns what putting the job in the disabled or enabled
+// state would do, the server dry run checking the current state of the job on
+// the controller.
+func (job *Job) planStateChange(clt *apiclient.ApiClient, state string, dryRun string) (ActionResult, error) {
+	if dryRun == DRY_RUN_SERVER {
+		isEnabled, err := job.JenkinsJob.IsEnabled(clt.Ctx)
+		if err != nil {
+			return ActionResult{}, err
+		}
+		if isEnabled == (state == "enabled") {
+			return newActionResult(
+				job, ACTION_STATUS_SKIPPED, "job %s is already in %s state on the controller", job.Name, state,
+			), nil
+		}
+	}
+	return newActionResult(job, ACTION_STATUS_PLANNED, "job %s would be %s", job.Name, state), nil
+}
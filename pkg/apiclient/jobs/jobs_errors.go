@@ -0,0 +1,53 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalid is matched with errors.Is by the errors of the values given by
+// the user which are not valid, like a pattern, a status or a build.
+var ErrInvalid = errors.New("invalid value")
+
+// ErrNotFound is matched with errors.Is by the errors of the jobs and the
+// builds which do not exist.
+var ErrNotFound = errors.New("not found")
+
+// kindError is an error whose message is formatted for the user, matching
+// its kind with errors.Is.
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (err *kindError) Error() string {
+	return err.message
+}
+
+func (err *kindError) Is(target error) bool {
+	return target == err.kind
+}
+
+func invalidError(format string, args ...interface{}) error {
+	return &kindError{kind: ErrInvalid, message: fmt.Sprintf(format, args...)}
+}
+
+func notFoundError(format string, args ...interface{}) error {
+	return &kindError{kind: ErrNotFound, message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"time"
+
+	"github.com/bndr/gojenkins"
+)
+
+// BuildHistory returns the last builds of the job, the most recent first,
+// every build being returned when count is 0.
+func (job *Job) BuildHistory(clt *apiclient.ApiClient, count int) ([]*gojenkins.Build, error) {
+	buildIds, err := job.JenkinsJob.GetAllBuildIds(clt.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 && len(buildIds) > count {
+		buildIds = buildIds[:count]
+	}
+
+	builds := make([]*gojenkins.Build, len(buildIds))
+	errs := make([]error, len(buildIds))
+	clt.RunConcurrently(len(buildIds), func(i int) {
+		builds[i], errs[i] = job.JenkinsJob.GetBuild(clt.Ctx, buildIds[i].Number)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return builds, nil
+}
+
+// BuildRecord is the summary of a build used to compute statistics.
+type BuildRecord struct {
+	Number    int64
+	Result    string
+	Building  bool
+	Timestamp time.Time
+	Duration  time.Duration
+}
+
+// GetBuildRecords returns the summary of the last builds of the job located
+// at fullName, the most recent first, in a single request.
+func GetBuildRecords(clt *apiclient.ApiClient, fullName string, count int) ([]BuildRecord, error) {
+	var response struct {
+		AllBuilds []struct {
+			Number    int64
+			Result    string
+			Building  bool
+			Timestamp int64
+			Duration  int64
+		}
+	}
+	_, err := clt.Jenkins.Requester.GetJSON(
+		clt.Ctx, JobBase(fullName), &response,
+		map[string]string{
+			"tree": fmt.Sprintf("allBuilds[number,result,building,timestamp,duration]{0,%d}", count),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	records := []BuildRecord{}
+	for _, build := range response.AllBuilds {
+		if len(records) == count {
+			break
+		}
+		records = append(records, BuildRecord{
+			Number:    build.Number,
+			Result:    build.Result,
+			Building:  build.Building,
+			Timestamp: time.Unix(0, build.Timestamp*int64(time.Millisecond)),
+			Duration:  time.Duration(build.Duration) * time.Millisecond,
+		})
+	}
+	return records, nil
+}
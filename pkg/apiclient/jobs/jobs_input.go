@@ -0,0 +1,294 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const STAGE_STATUS_PAUSED = "PAUSED_PENDING_INPUT"
+
+// InputParameter is a parameter asked by an input step.
+type InputParameter struct {
+	Name        string
+	Type        string
+	Description string
+	Definition  struct {
+		DefaultParameterValue *struct {
+			Value interface{}
+		}
+	}
+}
+
+// PendingInput is an input step waiting for an approval.
+type PendingInput struct {
+	Run         *PipelineRun `json:"-"`
+	ID          string
+	Message     string
+	Submitter   string
+	ProceedText string
+	Inputs      []InputParameter
+}
+
+// defaultValue returns the default value of the parameter, or an empty
+// string when it has none.
+func (parameter *InputParameter) defaultValue() string {
+	if parameter.Definition.DefaultParameterValue == nil ||
+		parameter.Definition.DefaultParameterValue.Value == nil {
+		return ""
+	}
+	return fmt.Sprint(parameter.Definition.DefaultParameterValue.Value)
+}
+
+// getPausedRuns returns the recent builds of the job which wait for an input,
+// the jobs which are not pipelines having none.
+func (job *Job) getPausedRuns(clt *apiclient.ApiClient) ([]*PipelineRun, error) {
+	runs := []*PipelineRun{}
+	err := getWorkflowJSON(clt, JobBase(job.Name)+"/wfapi/runs", &runs)
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	paused := []*PipelineRun{}
+	for _, run := range runs {
+		if run.Status == STAGE_STATUS_PAUSED {
+			run.Job = job.Name
+			paused = append(paused, run)
+		}
+	}
+	return paused, nil
+}
+
+// GetPendingInputs returns the input steps waiting in the recent builds of
+// the jobs.
+func (jobs *Jobs) GetPendingInputs(clt *apiclient.ApiClient) ([]PendingInput, error) {
+	inputs := make([][]PendingInput, len(jobs.Jobs))
+	errs := make([]error, len(jobs.Jobs))
+	clt.RunConcurrently(len(jobs.Jobs), func(i int) {
+		runs, err := jobs.Jobs[i].getPausedRuns(clt)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		for _, run := range runs {
+			runInputs, err := run.GetPendingInputs(clt)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			inputs[i] = append(inputs[i], runInputs...)
+		}
+	})
+	pending := []PendingInput{}
+	for i := range jobs.Jobs {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		pending = append(pending, inputs[i]...)
+	}
+	return pending, nil
+}
+
+// GetPendingInputs returns the input steps waiting in the build.
+func (run *PipelineRun) GetPendingInputs(clt *apiclient.ApiClient) ([]PendingInput, error) {
+	inputs := []PendingInput{}
+	err := getWorkflowJSON(clt, run.base()+"/wfapi/pendingInputActions", &inputs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range inputs {
+		inputs[i].Run = run
+	}
+	return inputs, nil
+}
+
+// GetPendingInput returns the input step of the build with the given id, or
+// its only input step when id is empty.
+func (run *PipelineRun) GetPendingInput(clt *apiclient.ApiClient, id string) (*PendingInput, error) {
+	inputs, err := run.GetPendingInputs(clt)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(inputs))
+	for i := range inputs {
+		if inputs[i].ID == id {
+			return &inputs[i], nil
+		}
+		ids[i] = inputs[i].ID
+	}
+	switch {
+	case len(inputs) == 0:
+		return nil, fmt.Errorf("%s #%s does not wait for an input", run.Job, run.ID)
+	case id != "":
+		return nil, fmt.Errorf(
+			"%s #%s has no input %s (possible values: %s)", run.Job, run.ID, id, strings.Join(ids, ", "),
+		)
+	case len(inputs) > 1:
+		return nil, fmt.Errorf(
+			"%s #%s waits for several inputs, choose one with --id (possible values: %s)",
+			run.Job, run.ID, strings.Join(ids, ", "),
+		)
+	}
+	return &inputs[0], nil
+}
+
+// WaitingTime returns the time elapsed since the build paused, which is the
+// pause time of the stage waiting for the input.
+func (input *PendingInput) WaitingTime() time.Duration {
+	for _, stage := range input.Run.Stages {
+		if stage.Status == STAGE_STATUS_PAUSED {
+			return time.Duration(stage.PauseDuration) * time.Millisecond
+		}
+	}
+	return 0
+}
+
+// Parameters returns the parameters of the input with their default value.
+func (input *PendingInput) Parameters() string {
+	parameters := make([]string, len(input.Inputs))
+	for i, parameter := range input.Inputs {
+		parameters[i] = parameter.Name + "=" + parameter.defaultValue()
+	}
+	return strings.Join(parameters, ", ")
+}
+
+func (input *PendingInput) post(
+	clt *apiclient.ApiClient, href string, data url.Values, query map[string]string,
+) error {
+	response, err := clt.Jenkins.Requester.Post(
+		clt.Ctx, input.Run.base()+href, strings.NewReader(data.Encode()), nil, query,
+	)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != 200 {
+		return errors.New(strconv.Itoa(response.StatusCode))
+	}
+	return nil
+}
+
+type inputParameterValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// parameterValues returns the values the parameters of the input are
+// submitted with, the given values or else the default ones.
+func (input *PendingInput) parameterValues(values map[string]string) ([]inputParameterValue, error) {
+	if len(input.Inputs) == 0 && len(values) > 0 {
+		return nil, fmt.Errorf("input %s has no parameter", input.ID)
+	}
+	parameters := []inputParameterValue{}
+	names := make([]string, len(input.Inputs))
+	known := map[string]bool{}
+	for i, parameter := range input.Inputs {
+		value, ok := values[parameter.Name]
+		if !ok {
+			value = parameter.defaultValue()
+		}
+		parameters = append(parameters, inputParameterValue{parameter.Name, value})
+		names[i] = parameter.Name
+		known[parameter.Name] = true
+	}
+	for name := range values {
+		if !known[name] {
+			return nil, fmt.Errorf(
+				"input %s has no parameter %s (possible values: %s)", input.ID, name, strings.Join(names, ", "),
+			)
+		}
+	}
+	return parameters, nil
+}
+
+// ApprovalParameters returns the parameters the input would be approved
+// with, like NAME=VALUE, without submitting it.
+func (input *PendingInput) ApprovalParameters(values map[string]string) (string, error) {
+	parameters, err := input.parameterValues(values)
+	if err != nil {
+		return "", err
+	}
+	texts := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		texts[i] = parameter.Name + "=" + parameter.Value
+	}
+	return strings.Join(texts, ", "), nil
+}
+
+// Approve submits the input with the given values of its parameters, the
+// other parameters keeping their default value.
+func (input *PendingInput) Approve(clt *apiclient.ApiClient, values map[string]string) (err error) {
+	defer func() { input.recordAudit(clt, "input-approve", "approved", err) }()
+	parameters, err := input.parameterValues(values)
+	if err != nil {
+		return err
+	}
+	if len(input.Inputs) == 0 {
+		return input.post(clt, "/input/"+input.ID+"/proceedEmpty", url.Values{}, nil)
+	}
+	body, err := json.Marshal(map[string]interface{}{"parameter": parameters})
+	if err != nil {
+		return err
+	}
+	data := url.Values{}
+	data.Set("json", string(body))
+	return input.post(clt, "/wfapi/inputSubmit", data, map[string]string{"inputId": input.ID})
+}
+
+// Abort rejects the input, which aborts the build.
+func (input *PendingInput) Abort(clt *apiclient.ApiClient) (err error) {
+	defer func() { input.recordAudit(clt, "input-abort", "aborted", err) }()
+	return input.post(clt, "/input/"+input.ID+"/abort", url.Values{}, nil)
+}
+
+// recordAudit records the submission of the input, done telling what was
+// done with it.
+func (input *PendingInput) recordAudit(clt *apiclient.ApiClient, action string, done string, err error) {
+	result := newNamedActionResult(
+		input.Run.Job, ACTION_STATUS_DONE, "Input %s of %s #%s %s", input.ID, input.Run.Job, input.Run.ID, done,
+	)
+	recordJobAudit(clt, action, input.Run.Job, DRY_RUN_NONE, result, err)
+}
+
+func PrintPendingInputsTable(inputs []PendingInput) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Build", "Id", "Message", "Submitter", "Parameters", "Waiting"})
+	for i := range inputs {
+		input := &inputs[i]
+		table.Append([]string{
+			input.Run.Job,
+			input.Run.ID,
+			input.ID,
+			input.Message,
+			input.Submitter,
+			input.Parameters(),
+			input.WaitingTime().Round(time.Second).String(),
+		})
+	}
+	table.Render()
+}
@@ -17,9 +17,11 @@ limitations under the License.
 package jobs
 
 import (
-	"errors"
 	"jenkinsctl/pkg/apiclient"
+	"path"
+	"regexp"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -35,8 +37,12 @@ func (job *Job) parseJenkinsJobWithBuilds(
 	clt *apiclient.ApiClient, jenkinsJob JenkinsJobNum,
 ) error {
 	job.Id = jenkinsJob.Id
-	job.Name = jenkinsJob.Job.GetName()
+	job.Name = jenkinsJob.Job.Raw.FullName
+	if job.Name == "" {
+		job.Name = jenkinsJob.Job.GetName()
+	}
 	job.JenkinsJob = jenkinsJob.Job
+	job.Disabled = jenkinsJob.Job.Raw.Color == JOB_COLOR_DISABLED
 
 	last_build, err := jenkinsJob.Job.GetLastBuild(clt.Ctx)
 	if err != nil {
@@ -55,15 +61,17 @@ func (job *Job) parseJenkinsJobWithBuilds(
 	job.LastBuildCreationDate = last_build.GetTimestamp()
 	job.IsRunning = last_build.Raw.Building
 	job.Result = last_build.GetResult()
+	job.Success = job.Result == gojenkins.STATUS_SUCCESS
 	job.JenkinsLastBuild = last_build
 	return nil
 }
 
 func (job *Job) getJobByName(clt *apiclient.ApiClient, name string) error {
-	jenkinsJob, err := clt.Jenkins.GetJob(clt.Ctx, name)
+	jobName, parents := SplitJobPath(name)
+	jenkinsJob, err := clt.Jenkins.GetJob(clt.Ctx, jobName, parents...)
 	if err != nil {
 		if err.Error() == "404" {
-			return errors.New("job not found")
+			return notFoundError("job %s not found", name)
 		}
 		return err
 	}
@@ -75,8 +83,56 @@ func (job *Job) getJobByName(clt *apiclient.ApiClient, name string) error {
 	return nil
 }
 
-func (jobs *Jobs) getAllJobs(clt *apiclient.ApiClient) error {
-	jenkinsJobs, err := clt.Jenkins.GetAllJobs(clt.Ctx)
+// appendInnerJobs appends the jobs of a folder and, recursively, of its
+// sub-folders to jenkinsJobs, the folders themselves being left out.
+func appendInnerJobs(
+	clt *apiclient.ApiClient, jenkinsJobs []*gojenkins.Job, folder *gojenkins.Job,
+) ([]*gojenkins.Job, error) {
+	innerJobs, err := folder.GetInnerJobs(clt.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, innerJob := range innerJobs {
+		if !isFolderItem(innerJob.Raw) {
+			jenkinsJobs = append(jenkinsJobs, innerJob)
+			continue
+		}
+		jenkinsJobs, err = appendInnerJobs(clt, jenkinsJobs, innerJob)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return jenkinsJobs, nil
+}
+
+// listJenkinsJobs returns the jobs of the root of Jenkins and, when
+// recursive, the jobs of the folders, the folders themselves being left out
+// so that no action is run on them.
+func listJenkinsJobs(clt *apiclient.ApiClient, recursive bool) ([]*gojenkins.Job, error) {
+	rootJobs, err := clt.Jenkins.GetAllJobs(clt.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	jenkinsJobs := []*gojenkins.Job{}
+	for _, rootJob := range rootJobs {
+		if !isFolderItem(rootJob.Raw) {
+			jenkinsJobs = append(jenkinsJobs, rootJob)
+			continue
+		}
+		if recursive {
+			jenkinsJobs, err = appendInnerJobs(clt, jenkinsJobs, rootJob)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return jenkinsJobs, nil
+}
+
+// getAllJobs gets the jobs of the root of Jenkins, with the jobs of the
+// folders when recursive.
+func (jobs *Jobs) getAllJobs(clt *apiclient.ApiClient, recursive bool) error {
+	jenkinsJobs, err := listJenkinsJobs(clt, recursive)
 	if err != nil {
 		return err
 	}
@@ -92,6 +148,7 @@ func (jobs *Jobs) getAllJobs(clt *apiclient.ApiClient) error {
 	close(doGetJobsBuilds)
 
 	jobsWithBuilds := make(chan Job, len(jenkinsJobsNum))
+	errs := make(chan error, len(jenkinsJobsNum))
 	var wg sync.WaitGroup
 	for i := 0; i < clt.ClientConfig.MaxConcurentRequests; i++ {
 		wg.Add(1)
@@ -100,7 +157,8 @@ func (jobs *Jobs) getAllJobs(clt *apiclient.ApiClient) error {
 				job := Job{}
 				err := job.parseJenkinsJobWithBuilds(clt, jenkinsJobNum)
 				if err != nil {
-					panic(err)
+					errs <- err
+					continue
 				}
 				jobsWithBuilds <- job
 			}
@@ -109,6 +167,10 @@ func (jobs *Jobs) getAllJobs(clt *apiclient.ApiClient) error {
 	}
 	wg.Wait()
 	close(jobsWithBuilds)
+	close(errs)
+	if err, ok := <-errs; ok {
+		return err
+	}
 
 	for job := range jobsWithBuilds {
 		jobs.Jobs = append(jobs.Jobs, job)
@@ -119,28 +181,89 @@ func (jobs *Jobs) getAllJobs(clt *apiclient.ApiClient) error {
 	return nil
 }
 
+// getJobsByName gets the jobs from their full name, without listing all the
+// jobs of Jenkins.
+func (jobs *Jobs) getJobsByName(clt *apiclient.ApiClient, names []string) error {
+	jobs.Jobs = make([]Job, len(names))
+	errs := make([]error, len(names))
+	clt.RunConcurrently(len(names), func(i int) {
+		errs[i] = jobs.Jobs[i].getJobByName(clt, names[i])
+		jobs.Jobs[i].Id = i
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasGlobPattern(names []string) bool {
+	for _, name := range names {
+		if strings.ContainsAny(name, "*?[\\") {
+			return true
+		}
+	}
+	return false
+}
+
+func checkPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return invalidError("invalid pattern %s: %s", pattern, err)
+		}
+	}
+	return nil
+}
+
 func (jobs *Jobs) GetFilteredJobs(
 	clt *apiclient.ApiClient, filter *JobsFilterParams,
 ) error {
-	if filter.Name != "" {
-		job := Job{}
-		err := job.getJobByName(clt, filter.Name)
+	if err := checkPatterns(filter.Names); err != nil {
+		return err
+	}
+	if err := checkPatterns(filter.Exclude); err != nil {
+		return err
+	}
+	statusFilter, err := ParseStatusFilter(filter.Status)
+	if err != nil {
+		return err
+	}
+	var regex *regexp.Regexp
+	if filter.Regex != "" {
+		regex, err = regexp.Compile(filter.Regex)
 		if err != nil {
-			return err
+			return invalidError("invalid regex %s: %s", filter.Regex, err)
 		}
-		jobs.Jobs = []Job{job}
-		return nil
 	}
+
+	// Exact names are got one by one, which also allows to get the jobs
+	// inside folders. Patterns and regexes can match the full name of the
+	// jobs inside folders, so the folders are then listed recursively. The
+	// folders are never selected, the actions on them being meaningless or
+	// acting on all their jobs.
 	jobsInput := Jobs{}
-	err := jobsInput.getAllJobs(clt)
+	if len(filter.Names) > 0 && !hasGlobPattern(filter.Names) && regex == nil {
+		err = jobsInput.getJobsByName(clt, filter.Names)
+	} else {
+		err = jobsInput.getAllJobs(clt, filter.Recursive || hasGlobPattern(filter.Names) || regex != nil)
+	}
 	if err != nil {
 		return err
 	}
 
+	now := time.Now()
 	for _, job := range jobsInput.Jobs {
-		if !job.checkJobStatusMatch(filter.Status) ||
-			!job.checkJobMinimumAgeMatch(filter.AgeMin) ||
-			!job.checkJobMaximumAgeMatch(filter.AgeMax) {
+		if !job.checkJobNameMatch(filter.Names) ||
+			!job.checkJobRegexMatch(regex) ||
+			!job.checkJobExcludeMatch(filter.Exclude) ||
+			!job.checkJobStatusMatch(statusFilter) ||
+			!job.checkJobMinimumAgeMatch(filter.AgeMin, now) ||
+			!job.checkJobMaximumAgeMatch(filter.AgeMax, now) ||
+			!job.checkJobSinceMatch(filter.Since) ||
+			!job.checkJobUntilMatch(filter.Until) ||
+			job.IsFolder() ||
+			(filter.Selector != nil && !filter.Selector.Match(&job)) {
 			continue
 		}
 		jobs.Jobs = append(jobs.Jobs, job)
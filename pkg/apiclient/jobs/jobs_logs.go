@@ -0,0 +1,86 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"jenkinsctl/pkg/apiclient"
+	"strconv"
+	"time"
+)
+
+// BuildLogChunk is a part of the console log of a build, More telling whether
+// the build is still writing its log.
+type BuildLogChunk struct {
+	Text   string
+	Offset int64
+	More   bool
+}
+
+// GetBuildLogChunk returns the console log of a build of the job located at
+// fullName from the offset start.
+func GetBuildLogChunk(clt *apiclient.ApiClient, fullName string, number int64, start int64) (*BuildLogChunk, error) {
+	chunk := &BuildLogChunk{}
+	response, err := clt.Jenkins.Requester.Get(
+		clt.Ctx, JobBase(fullName)+"/"+strconv.FormatInt(number, 10)+"/logText/progressiveText",
+		&chunk.Text, map[string]string{"start": strconv.FormatInt(start, 10)},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != 200 {
+		return nil, errors.New(strconv.Itoa(response.StatusCode))
+	}
+	chunk.More = response.Header.Get("X-More-Data") != ""
+	chunk.Offset, err = strconv.ParseInt(response.Header.Get("X-Text-Size"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return chunk, nil
+}
+
+// FollowBuildLog calls write with the console log of a build as it is
+// written, checking for new output every interval, until the build is over,
+// the context is done or write fails. Without follow, only the current log is
+// written.
+func FollowBuildLog(
+	ctx context.Context, clt *apiclient.ApiClient, fullName string, number int64, follow bool,
+	interval time.Duration, write func(text string) error,
+) error {
+	var offset int64
+	for {
+		chunk, err := GetBuildLogChunk(clt, fullName, number, offset)
+		if err != nil {
+			return err
+		}
+		if chunk.Text != "" {
+			if err := write(chunk.Text); err != nil {
+				return err
+			}
+		}
+		offset = chunk.Offset
+		if !follow || !chunk.More {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"errors"
+	"jenkinsctl/pkg/apiclient"
+	"strconv"
+	"strings"
+
+	"github.com/bndr/gojenkins"
+)
+
+const FOLDER_CLASS = "com.cloudbees.hudson.plugins.folder.Folder"
+
+// isFolderItem reports whether the item contains jobs instead of being built.
+func isFolderItem(raw *gojenkins.JobResponse) bool {
+	return raw != nil && (raw.Class == FOLDER_CLASS || len(raw.Jobs) > 0)
+}
+
+// IsFolder reports whether the job is a folder, which contains jobs instead of
+// being built.
+func (job *Job) IsFolder() bool {
+	return job.JenkinsJob != nil && isFolderItem(job.JenkinsJob.Raw)
+}
+
+// SplitJobPath splits the full name of a job ("folder/sub-folder/job") into
+// the job name and the names of its parent folders.
+func SplitJobPath(fullName string) (string, []string) {
+	names := strings.Split(strings.Trim(fullName, "/"), "/")
+	return names[len(names)-1], names[:len(names)-1]
+}
+
+// JobBase returns the url path of a job from its full name.
+func JobBase(fullName string) string {
+	return "/job/" + strings.Join(strings.Split(strings.Trim(fullName, "/"), "/"), "/job/")
+}
+
+// NewJenkinsJob returns the job located at fullName without querying Jenkins.
+func NewJenkinsJob(clt *apiclient.ApiClient, fullName string) *gojenkins.Job {
+	return &gojenkins.Job{
+		Jenkins: clt.Jenkins,
+		Raw:     new(gojenkins.JobResponse),
+		Base:    JobBase(fullName),
+	}
+}
+
+// GetJobConfig returns the config.xml of the job located at fullName, or the
+// "404" error when the job does not exist.
+func GetJobConfig(clt *apiclient.ApiClient, fullName string) (string, error) {
+	var config string
+	response, err := clt.Jenkins.Requester.GetXML(
+		clt.Ctx, JobBase(fullName)+"/config.xml", &config, nil,
+	)
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode != 200 {
+		return "", errors.New(strconv.Itoa(response.StatusCode))
+	}
+	return config, nil
+}
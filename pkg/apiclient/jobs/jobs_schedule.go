@@ -2,8 +2,11 @@ package jobs
 
 import (
 	"fmt"
+	"html"
 	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/diff"
 	"regexp"
+	"strings"
 
 	"github.com/beevik/etree"
 )
@@ -16,20 +19,30 @@ func selectOrCreateElement(root *etree.Element, name string) *etree.Element {
 	return element
 }
 
-func (jobs *Jobs) Schedule(clt *apiclient.ApiClient, schedule string) error {
-	fmt.Println("Scheduling jobs...")
-
-	var xmlHeaderRegex = regexp.MustCompile(`^<\?xml.*\?>`)
-
-	for _, job := range jobs.Jobs {
-		rawXml, _ := job.JenkinsJob.GetConfig(clt.Ctx)
-		rawXml = xmlHeaderRegex.ReplaceAllString(rawXml, "")
-
-		doc := etree.NewDocument()
-		if err := doc.ReadFromString(rawXml); err != nil {
-			return err
+// ScheduleJobs sets the schedule of the timer trigger of the pipeline jobs,
+// and returns the outcome for each job until an error occurs. On a dry run,
+// the configurations are not updated and the outcome gives the diff of the
+// config.xml of each job, the server dry run validating the schedule.
+func (jobs *Jobs) ScheduleJobs(clt *apiclient.ApiClient, schedule string, dryRun string) (results []ActionResult, err error) {
+	defer func() { jobs.recordAudit(clt, "schedule", dryRun, results, err) }()
+	results = []ActionResult{}
+	for i := range jobs.Jobs {
+		job := &jobs.Jobs[i]
+		doc, err := job.readConfig(clt)
+		if err != nil {
+			return results, err
+		}
+		currentXml, err := doc.WriteToString()
+		if err != nil {
+			return results, err
 		}
 		flowDefinition := doc.SelectElement("flow-definition")
+		if flowDefinition == nil {
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_FAILED, "job %s is not a pipeline job, it can not be scheduled", job.Name,
+			))
+			continue
+		}
 		properties := selectOrCreateElement(flowDefinition, "properties")
 		pipelineTriggersJobProperty := selectOrCreateElement(
 			properties, "org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty",
@@ -40,7 +53,9 @@ func (jobs *Jobs) Schedule(clt *apiclient.ApiClient, schedule string) error {
 
 		before := spec.Text()
 		if before == schedule {
-			fmt.Printf("%s schedule is already defined on %s job\n", schedule, job.Name)
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_SKIPPED, "%s schedule is already defined on %s job", schedule, job.Name,
+			))
 			continue
 		}
 		spec.SetText(schedule)
@@ -48,14 +63,93 @@ func (jobs *Jobs) Schedule(clt *apiclient.ApiClient, schedule string) error {
 		doc.Indent(2)
 		newXml, err := doc.WriteToString()
 		if err != nil {
-			return err
+			return results, err
 		}
 
+		if dryRun != DRY_RUN_NONE {
+			result, err := job.planSchedule(clt, schedule, dryRun, currentXml, newXml)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
+			continue
+		}
 		err = job.JenkinsJob.UpdateConfig(clt.Ctx, newXml)
 		if err != nil {
-			return err
+			return results, err
 		}
-		fmt.Printf("job %s is now scheduled (%s)\n", job.Name, schedule)
+		results = append(results, newActionResult(
+			job, ACTION_STATUS_DONE, "job %s is now scheduled (%s)", job.Name, schedule,
+		))
+	}
+	return results, nil
+}
+
+// planSchedule returns the change of the configuration of the job, the server
+// dry run asking the controller to check the schedule like the job
+// configuration page does.
+func (job *Job) planSchedule(
+	clt *apiclient.ApiClient, schedule string, dryRun string, currentXml string, newXml string,
+) (ActionResult, error) {
+	result := newActionResult(
+		job, ACTION_STATUS_PLANNED, "job %s would be scheduled (%s)", job.Name, schedule,
+	)
+	result.Diff = diff.Unified(
+		job.Name+"/config.xml (current)", job.Name+"/config.xml (new)", currentXml, newXml, 3,
+	)
+	if dryRun != DRY_RUN_SERVER {
+		return result, nil
+	}
+	kind, message, err := job.checkSchedule(clt, schedule)
+	if err != nil {
+		return result, err
+	}
+	switch kind {
+	case "":
+		result.Message += ", the controller did not validate the schedule: " + message
+	case "error":
+		result.Status = ACTION_STATUS_FAILED
+		result.Message = fmt.Sprintf("job %s can not be scheduled (%s): %s", job.Name, schedule, message)
+	default:
+		if message != "" {
+			result.Message += ": " + message
+		}
+	}
+	return result, nil
+}
+
+var (
+	formValidationKindRegex = regexp.MustCompile(`^\s*<div[^>]*class=["']?(\w+)`)
+	htmlTagRegex            = regexp.MustCompile(`<[^>]*>`)
+)
+
+// checkSchedule sends the schedule to the validation of the timer trigger and
+// returns the kind of the answer (ok, warning or error) with its message, or
+// an empty kind with the reason when the controller does not validate it.
+func (job *Job) checkSchedule(clt *apiclient.ApiClient, schedule string) (string, string, error) {
+	var text string
+	response, err := clt.Jenkins.Requester.Get(
+		clt.Ctx, job.JenkinsJob.Base+"/descriptorByName/hudson.triggers.TimerTrigger/checkSpec", &text,
+		map[string]string{"value": schedule},
+	)
+	if err != nil {
+		return "", "", err
+	}
+	if response.StatusCode != 200 {
+		return "", fmt.Sprintf("validation answered with status %d", response.StatusCode), nil
+	}
+	kind, message := parseFormValidation(text)
+	return kind, message, nil
+}
+
+// parseFormValidation returns the kind (ok, warning or error) and the text of
+// the HTML answer of a form validation of Jenkins, like
+// <div class=warning><img ...>Spread load evenly by using &#8216;H ...</div>.
+func parseFormValidation(text string) (string, string) {
+	kind := "ok"
+	if match := formValidationKindRegex.FindStringSubmatch(text); match != nil {
+		kind = match[1]
 	}
-	return nil
+	message := strings.TrimSpace(html.UnescapeString(htmlTagRegex.ReplaceAllString(text, "")))
+	return kind, message
 }
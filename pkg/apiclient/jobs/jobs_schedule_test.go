@@ -0,0 +1,61 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"testing"
+)
+
+func TestParseFormValidation(t *testing.T) {
+	tests := []struct {
+		text        string
+		wantKind    string
+		wantMessage string
+	}{
+		{`<div/>`, "ok", ""},
+		{"", "ok", ""},
+		{
+			`<div class=ok><img src='/static/4a5f/images/none.gif' height=16 width=1>` +
+				`Would last have run at Monday, October 19, 2026 at 8:12:00 AM Coordinated Universal Time; ` +
+				`would next run at Tuesday, October 20, 2026 at 8:12:00 AM Coordinated Universal Time.</div>`,
+			"ok",
+			"Would last have run at Monday, October 19, 2026 at 8:12:00 AM Coordinated Universal Time; " +
+				"would next run at Tuesday, October 20, 2026 at 8:12:00 AM Coordinated Universal Time.",
+		},
+		{
+			`<div class=warning><img src='/static/4a5f/images/none.gif' height=16 width=1>` +
+				`Spread load evenly by using &#8216;H 8 * * *&#8217; rather than &#8216;0 8 * * *&#8217;</div>`,
+			"warning",
+			"Spread load evenly by using ‘H 8 * * *’ rather than ‘0 8 * * *’",
+		},
+		{
+			"\n<div class=\"error\"><img src=\"/static/4a5f/images/none.gif\" height=16 width=1>" +
+				"Invalid input: &quot;61 * * * *&quot;: line 1:1: 61 is an invalid value. Must be within 0 and 59</div>\n",
+			"error",
+			`Invalid input: "61 * * * *": line 1:1: 61 is an invalid value. Must be within 0 and 59`,
+		},
+		{`<div class='warning'>No schedules so will never run</div>`, "warning", "No schedules so will never run"},
+		{`Not a form validation`, "ok", "Not a form validation"},
+	}
+	for _, test := range tests {
+		kind, message := parseFormValidation(test.text)
+		if kind != test.wantKind || message != test.wantMessage {
+			t.Errorf("parseFormValidation(%q) = %q, %q, want %q, %q",
+				test.text, kind, message, test.wantKind, test.wantMessage)
+		}
+	}
+}
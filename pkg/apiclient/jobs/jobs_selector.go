@@ -0,0 +1,61 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"jenkinsctl/pkg/selector"
+	"strings"
+	"time"
+)
+
+// SelectorSchema gives the fields of a job usable in a selector expression.
+var SelectorSchema = selector.Schema{
+	"name":     selector.TypeString,
+	"status":   selector.TypeString,
+	"result":   selector.TypeString,
+	"age":      selector.TypeDuration,
+	"duration": selector.TypeDuration,
+	"build":    selector.TypeNumber,
+	"running":  selector.TypeBool,
+	"disabled": selector.TypeBool,
+}
+
+// Field returns the value of a field of SelectorSchema, the age is the time
+// elapsed since the start of the last build and the duration of a running
+// build is its current duration.
+func (job *Job) Field(name string) interface{} {
+	switch name {
+	case "name":
+		return job.Name
+	case "status":
+		return job.Status()
+	case "result":
+		return strings.ToLower(job.Result)
+	case "age":
+		return time.Since(job.LastBuildCreationDate)
+	case "duration":
+		return job.lastBuildDuration()
+	case "build":
+		return float64(job.lastBuildNumber())
+	case "running":
+		return job.IsRunning
+	case "disabled":
+		return job.Disabled
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"sort"
+	"strings"
+)
+
+var jobSortKeys = map[string]func(a, b *JobSummary) bool{
+	"name": func(a, b *JobSummary) bool {
+		return a.Name < b.Name
+	},
+	"status": func(a, b *JobSummary) bool {
+		return statusIndex(a.Status) < statusIndex(b.Status)
+	},
+	// The youngest jobs come first, the jobs without build being the oldest.
+	"age": func(a, b *JobSummary) bool {
+		return a.lastBuildDate().After(b.lastBuildDate())
+	},
+	"duration": func(a, b *JobSummary) bool {
+		return a.LastBuildDuration < b.LastBuildDuration
+	},
+	"build-number": func(a, b *JobSummary) bool {
+		return a.LastBuildNumber < b.LastBuildNumber
+	},
+}
+
+// JobSortKeys lists the keys the jobs can be sorted by.
+var JobSortKeys = []string{"name", "status", "age", "duration", "build-number"}
+
+func statusIndex(status string) int {
+	for i, candidate := range JobStatuses {
+		if candidate == status {
+			return i
+		}
+	}
+	return len(JobStatuses)
+}
+
+func CheckSortKey(key string) error {
+	if _, ok := jobSortKeys[key]; !ok && key != "" {
+		return invalidError(
+			"%s is not accepted sort key (possible values: %s)",
+			key, strings.Join(JobSortKeys, ", "),
+		)
+	}
+	return nil
+}
+
+// SortSummaries sorts the jobs by the given key, the jobs keeping the order
+// of Jenkins when the key is empty. The jobs being equal for the key keep
+// their order, even when reversed.
+func SortSummaries(summaries []JobSummary, key string, reverse bool) error {
+	if err := CheckSortKey(key); err != nil {
+		return err
+	}
+	if key == "" {
+		if reverse {
+			for i, j := 0, len(summaries)-1; i < j; i, j = i+1, j-1 {
+				summaries[i], summaries[j] = summaries[j], summaries[i]
+			}
+		}
+		return nil
+	}
+	less := jobSortKeys[key]
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if reverse {
+			return less(&summaries[j], &summaries[i])
+		}
+		return less(&summaries[i], &summaries[j])
+	})
+	return nil
+}
+
+// LimitSummaries returns the first jobs, every job is kept when limit is 0.
+func LimitSummaries(summaries []JobSummary, limit int) []JobSummary {
+	if limit > 0 && len(summaries) > limit {
+		return summaries[:limit]
+	}
+	return summaries
+}
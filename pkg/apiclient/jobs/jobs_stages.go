@@ -0,0 +1,188 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"jenkinsctl/pkg/apiclient"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bndr/gojenkins"
+	"github.com/olekukonko/tablewriter"
+)
+
+const STAGE_STATUS_FAILED = "FAILED"
+
+// PipelineStage is a stage of a pipeline build, or a step of a stage, as
+// described by the workflow API of the pipeline stage view plugin.
+type PipelineStage struct {
+	ID                   string
+	Name                 string
+	Status               string
+	ParameterDescription string
+	StartTime            int64 `json:"startTimeMillis"`
+	Duration             int64 `json:"durationMillis"`
+	PauseDuration        int64 `json:"pauseDurationMillis"`
+	StageFlowNodes       []PipelineStage
+}
+
+// PipelineRun is a pipeline build with its stages.
+type PipelineRun struct {
+	Job           string `json:"-"`
+	ID            string
+	Name          string
+	Status        string
+	StartTime     int64 `json:"startTimeMillis"`
+	Duration      int64 `json:"durationMillis"`
+	PauseDuration int64 `json:"pauseDurationMillis"`
+	Stages        []PipelineStage
+}
+
+// htmlTags matches the markup of the console annotations in the logs.
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+// getWorkflowJSON gets an endpoint of the workflow API, which is not under
+// api/json like the rest of the Jenkins API.
+func getWorkflowJSON(clt *apiclient.ApiClient, href string, responseStruct interface{}) error {
+	response, err := clt.Jenkins.Requester.Get(clt.Ctx, href, responseStruct, nil)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != 200 {
+		return errors.New(strconv.Itoa(response.StatusCode))
+	}
+	return nil
+}
+
+// GetPipelineRun returns the stages of a build of the pipeline job located at
+// fullName, or of its last build when number is 0.
+func GetPipelineRun(clt *apiclient.ApiClient, fullName string, number int64) (*PipelineRun, error) {
+	build, buildName := "lastBuild", "last build"
+	if number > 0 {
+		build = strconv.FormatInt(number, 10)
+		buildName = "build " + build
+	}
+	run := &PipelineRun{}
+	err := getWorkflowJSON(clt, JobBase(fullName)+"/"+build+"/wfapi/describe", run)
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, fmt.Errorf(
+				"%s of job %s not found, or the job is not a pipeline", buildName, fullName,
+			)
+		}
+		return nil, err
+	}
+	run.Job = fullName
+	return run, nil
+}
+
+func (run *PipelineRun) base() string {
+	return JobBase(run.Job) + "/" + run.ID
+}
+
+// FailedStages returns the stages which have failed, the following stages
+// being usually not executed.
+func (run *PipelineRun) FailedStages() []PipelineStage {
+	failed := []PipelineStage{}
+	for _, stage := range run.Stages {
+		if stage.Status == STAGE_STATUS_FAILED {
+			failed = append(failed, stage)
+		}
+	}
+	return failed
+}
+
+// GetStageSteps returns the steps of a stage, which are not given by the
+// description of the build.
+func (run *PipelineRun) GetStageSteps(clt *apiclient.ApiClient, stage PipelineStage) ([]PipelineStage, error) {
+	node := PipelineStage{}
+	err := getWorkflowJSON(clt, run.base()+"/execution/node/"+stage.ID+"/wfapi/describe", &node)
+	if err != nil {
+		return nil, err
+	}
+	return node.StageFlowNodes, nil
+}
+
+// GetStepLog returns the log of a step, which is only the end of the log
+// when HasMore is true.
+func (run *PipelineRun) GetStepLog(clt *apiclient.ApiClient, step PipelineStage) (*gojenkins.PipelineNodeLog, error) {
+	log := &gojenkins.PipelineNodeLog{}
+	err := getWorkflowJSON(clt, run.base()+"/execution/node/"+step.ID+"/wfapi/log", log)
+	if err != nil {
+		return nil, err
+	}
+	log.Text = html.UnescapeString(htmlTags.ReplaceAllString(log.Text, ""))
+	return log, nil
+}
+
+func formatMillis(millis int64) string {
+	return (time.Duration(millis) * time.Millisecond).Round(time.Second).String()
+}
+
+// PrintStagesTable prints the build and the given stages.
+func (run *PipelineRun) PrintStagesTable(stages []PipelineStage) {
+	fmt.Printf(
+		"%s #%s: %s, duration %s\n",
+		run.Job, run.ID, strings.ToLower(run.Status), formatMillis(run.Duration),
+	)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Stage", "Status", "Duration", "Pause"})
+	for _, stage := range stages {
+		table.Append([]string{
+			stage.Name,
+			strings.ToLower(stage.Status),
+			formatMillis(stage.Duration),
+			formatMillis(stage.PauseDuration),
+		})
+	}
+	table.Render()
+}
+
+// PrintFailedStagesLogs prints the log of the steps of the failed stages.
+func (run *PipelineRun) PrintFailedStagesLogs(clt *apiclient.ApiClient) error {
+	for _, stage := range run.FailedStages() {
+		steps, err := run.GetStageSteps(clt, stage)
+		if err != nil {
+			return err
+		}
+		for _, step := range steps {
+			log, err := run.GetStepLog(clt, step)
+			if err != nil {
+				return err
+			}
+			if log.Text == "" {
+				continue
+			}
+			title := step.Name
+			if step.ParameterDescription != "" {
+				title += " (" + step.ParameterDescription + ")"
+			}
+			fmt.Printf("\n==> %s / %s: %s\n", stage.Name, title, strings.ToLower(step.Status))
+			if log.HasMore {
+				fmt.Printf("[...] full log at %s%s\n", strings.TrimSuffix(clt.Jenkins.Server, "/"), log.ConsoleURL)
+			}
+			fmt.Print(strings.TrimRight(log.Text, "\n") + "\n")
+		}
+	}
+	return nil
+}
@@ -19,22 +19,91 @@ package jobs
 import (
 	"fmt"
 	"jenkinsctl/pkg/apiclient"
+	"strings"
 )
 
-func (jobs *Jobs) Start(clt *apiclient.ApiClient) error {
-	fmt.Println("Starting jobs...")
-	for _, job := range jobs.Jobs {
+// StartJobs starts the jobs which are not running, and returns the outcome
+// for each job until an error occurs. On a dry run, the jobs are not started
+// and the outcome gives the parameters their builds would get.
+func (jobs *Jobs) StartJobs(clt *apiclient.ApiClient, dryRun string) (results []ActionResult, err error) {
+	defer func() { jobs.recordAudit(clt, "start", dryRun, results, err) }()
+	results = []ActionResult{}
+	for i := range jobs.Jobs {
+		job := &jobs.Jobs[i]
 		if job.IsRunning {
-			fmt.Printf("job %s is already in started state\n", job.Name)
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_SKIPPED, "job %s is already in started state", job.Name,
+			))
 			continue
 		}
-		buildId, err := job.JenkinsJob.InvokeSimple(clt.Ctx, map[string]string{})
+		if dryRun != DRY_RUN_NONE {
+			result, err := job.planStart(clt, dryRun)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
+			continue
+		}
+		queueId, err := job.JenkinsJob.InvokeSimple(clt.Ctx, map[string]string{})
 		if err != nil {
-			return err
+			return results, err
+		}
+		// InvokeSimple returns no queue id when the job is already queued,
+		// no build being then started.
+		if queueId == 0 {
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_SKIPPED, "job %s is already queued, no build was started", job.Name,
+			))
+			continue
 		}
-		if buildId > 0 {
-			fmt.Printf("job %s is now in started state, build id: %d\n", job.Name, buildId)
+		result := newActionResult(
+			job, ACTION_STATUS_DONE, "job %s is now in started state, queue id: %d", job.Name, queueId,
+		)
+		result.QueueID = queueId
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// planStart returns what starting the job would do: like InvokeSimple, no
+// build is started when the job is already queued, and the build gets the
+// default values of the parameters. The server dry run also rejects the jobs
+// whose buildable flag is false. This check is made here on the state read
+// from the controller, Jenkins having no way to validate a build without
+// starting it.
+func (job *Job) planStart(clt *apiclient.ApiClient, dryRun string) (ActionResult, error) {
+	definitions, err := job.JenkinsJob.GetParameters(clt.Ctx)
+	if err != nil {
+		return ActionResult{}, err
+	}
+	if job.JenkinsJob.Raw.InQueue {
+		return newActionResult(
+			job, ACTION_STATUS_SKIPPED, "job %s is already queued, no build would be started", job.Name,
+		), nil
+	}
+	if dryRun == DRY_RUN_SERVER && !job.JenkinsJob.Raw.Buildable {
+		return newActionResult(
+			job, ACTION_STATUS_FAILED, "job %s can not be built on the controller", job.Name,
+		), nil
+	}
+	if len(definitions) == 0 {
+		return newActionResult(
+			job, ACTION_STATUS_PLANNED, "job %s would be started without parameters", job.Name,
+		), nil
+	}
+	parameters := map[string]string{}
+	values := []string{}
+	for _, definition := range definitions {
+		value := ""
+		if definition.DefaultParameterValue.Value != nil {
+			value = fmt.Sprint(definition.DefaultParameterValue.Value)
 		}
+		parameters[definition.Name] = value
+		values = append(values, definition.Name+"="+value)
 	}
-	return nil
+	result := newActionResult(
+		job, ACTION_STATUS_PLANNED, "job %s would be started with %s", job.Name, strings.Join(values, ", "),
+	)
+	result.Parameters = parameters
+	return result, nil
 }
@@ -0,0 +1,179 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import "strings"
+
+// JobStatuses lists the statuses of a job, see Job.Status.
+var JobStatuses = []string{
+	JOB_STATUS_RUNNING,
+	JOB_STATUS_SUCCESS,
+	JOB_STATUS_UNSTABLE,
+	JOB_STATUS_FAILED,
+	JOB_STATUS_ABORTED,
+	JOB_STATUS_NOTBUILT,
+	JOB_STATUS_NOBUILD,
+	JOB_STATUS_DISABLED,
+}
+
+// resultStatuses maps the result of a Jenkins build to a job status.
+var resultStatuses = map[string]string{
+	"SUCCESS":   JOB_STATUS_SUCCESS,
+	"UNSTABLE":  JOB_STATUS_UNSTABLE,
+	"FAILURE":   JOB_STATUS_FAILED,
+	"ABORTED":   JOB_STATUS_ABORTED,
+	"NOT_BUILT": JOB_STATUS_NOTBUILT,
+}
+
+// colorStatuses maps the color of a Jenkins job, without the _anime suffix
+// of the running jobs, to a job status.
+var colorStatuses = map[string]string{
+	"blue":     JOB_STATUS_SUCCESS,
+	"yellow":   JOB_STATUS_UNSTABLE,
+	"red":      JOB_STATUS_FAILED,
+	"aborted":  JOB_STATUS_ABORTED,
+	"notbuilt": JOB_STATUS_NOBUILD,
+	"disabled": JOB_STATUS_DISABLED,
+}
+
+// Status returns the status of the job: running while its last build is
+// running, then disabled for the disabled jobs, and otherwise the result of
+// its last build, the color of the job being used when the result is unknown.
+func (job *Job) Status() string {
+	if job.Disabled && !job.IsRunning {
+		return JOB_STATUS_DISABLED
+	}
+	return job.buildStatus()
+}
+
+// buildStatus returns the status of the last build of the job, running while
+// it is running, whether the job is disabled or not.
+func (job *Job) buildStatus() string {
+	if job.IsRunning {
+		return JOB_STATUS_RUNNING
+	}
+	if job.Result == JOB_STATUS_NOBUILD {
+		return JOB_STATUS_NOBUILD
+	}
+	if status, ok := resultStatuses[job.Result]; ok {
+		return status
+	}
+	if job.JenkinsJob != nil && job.JenkinsJob.Raw != nil {
+		color := strings.TrimSuffix(job.JenkinsJob.Raw.Color, "_anime")
+		if status, ok := colorStatuses[color]; ok {
+			return status
+		}
+	}
+	return strings.ToLower(job.Result)
+}
+
+// filterStatuses returns the statuses a job is filtered on: the status of its
+// last build, and disabled for the disabled jobs, so that a disabled job whose
+// last build failed matches both failure and disabled.
+func (job *Job) filterStatuses() []string {
+	statuses := []string{job.buildStatus()}
+	if job.Disabled && statuses[0] != JOB_STATUS_DISABLED {
+		statuses = append(statuses, JOB_STATUS_DISABLED)
+	}
+	return statuses
+}
+
+// StatusFilter selects jobs from their status, a job matches when one of its
+// statuses is one of Include, or any status when Include is empty, and none
+// of them is one of Exclude.
+type StatusFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// ParseStatusFilter parses a comma separated list of statuses, the statuses
+// prefixed with ! being excluded, for example "failure,unstable" or
+// "!success". The all status matches every job.
+func ParseStatusFilter(value string) (*StatusFilter, error) {
+	filter := &StatusFilter{}
+	for _, status := range strings.Split(value, ",") {
+		status = strings.ToLower(strings.TrimSpace(status))
+		excluded := strings.HasPrefix(status, "!")
+		status = strings.TrimPrefix(status, "!")
+		if status == JOB_STATUS_ALL && !excluded {
+			continue
+		}
+		if !containsStatus(JobStatuses, status) {
+			return nil, invalidError(
+				"%s is not accepted status (possible values: %s)",
+				status, strings.Join(append([]string{JOB_STATUS_ALL}, JobStatuses...), ", "),
+			)
+		}
+		if excluded {
+			filter.Exclude = append(filter.Exclude, status)
+		} else {
+			filter.Include = append(filter.Include, status)
+		}
+	}
+	return filter, nil
+}
+
+func containsStatus(statuses []string, status string) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (job *Job) checkJobStatusMatch(filter *StatusFilter) bool {
+	return filter.match(job.filterStatuses())
+}
+
+// MatchSummary reports whether the job of the summary matches the filter,
+// like the listing of the jobs filtered on their status.
+func (filter *StatusFilter) MatchSummary(summary *JobSummary) bool {
+	return filter.match(summary.filterStatuses())
+}
+
+func (filter *StatusFilter) match(statuses []string) bool {
+	included := len(filter.Include) == 0
+	for _, status := range statuses {
+		if containsStatus(filter.Exclude, status) {
+			return false
+		}
+		included = included || containsStatus(filter.Include, status)
+	}
+	return included
+}
+
+// filterStatuses returns the statuses the job of the summary is filtered on,
+// like Job.filterStatuses, the status of the last build of a disabled job
+// being found from its result.
+func (summary *JobSummary) filterStatuses() []string {
+	if !summary.Disabled {
+		return []string{summary.Status}
+	}
+	buildStatus := summary.Status
+	if buildStatus == JOB_STATUS_DISABLED {
+		if summary.LastBuildResult == "" {
+			buildStatus = JOB_STATUS_NOBUILD
+		} else if status, ok := resultStatuses[summary.LastBuildResult]; ok {
+			buildStatus = status
+		}
+	}
+	if buildStatus == JOB_STATUS_DISABLED {
+		return []string{buildStatus}
+	}
+	return []string{buildStatus, JOB_STATUS_DISABLED}
+}
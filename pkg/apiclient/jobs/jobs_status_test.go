@@ -0,0 +1,121 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStatusFilter(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    *StatusFilter
+		wantErr bool
+	}{
+		{"failure", &StatusFilter{Include: []string{"failure"}}, false},
+		{"failure,unstable", &StatusFilter{Include: []string{"failure", "unstable"}}, false},
+		{"!success", &StatusFilter{Exclude: []string{"success"}}, false},
+		{" Failure , !DISABLED ", &StatusFilter{Include: []string{"failure"}, Exclude: []string{"disabled"}}, false},
+		{"all", &StatusFilter{}, false},
+		{"all,!running", &StatusFilter{Exclude: []string{"running"}}, false},
+		{"!all", nil, true},
+		{"failed", nil, true},
+		{"success,!", nil, true},
+	}
+	for _, test := range tests {
+		got, err := ParseStatusFilter(test.value)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseStatusFilter(%q) error = %v, wantErr %v", test.value, err, test.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseStatusFilter(%q) = %+v, want %+v", test.value, got, test.want)
+		}
+	}
+}
+
+func TestJobFilterStatuses(t *testing.T) {
+	tests := []struct {
+		job  Job
+		want []string
+	}{
+		{Job{Result: "SUCCESS"}, []string{"success"}},
+		{Job{Result: JOB_STATUS_NOBUILD}, []string{"no_build"}},
+		{Job{Result: "FAILURE", IsRunning: true}, []string{"running"}},
+		{Job{Result: "FAILURE", Disabled: true}, []string{"failure", "disabled"}},
+		{Job{Result: JOB_STATUS_NOBUILD, Disabled: true}, []string{"no_build", "disabled"}},
+		{Job{Result: "SUCCESS", Disabled: true, IsRunning: true}, []string{"running", "disabled"}},
+	}
+	for _, test := range tests {
+		if got := test.job.filterStatuses(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("filterStatuses(%+v) = %v, want %v", test.job, got, test.want)
+		}
+	}
+}
+
+func TestSummaryFilterStatuses(t *testing.T) {
+	tests := []struct {
+		summary JobSummary
+		want    []string
+	}{
+		{JobSummary{Status: "success", LastBuildResult: "SUCCESS"}, []string{"success"}},
+		{JobSummary{Status: "disabled", Disabled: true, LastBuildResult: "FAILURE"}, []string{"failure", "disabled"}},
+		{JobSummary{Status: "disabled", Disabled: true}, []string{"no_build", "disabled"}},
+		{JobSummary{Status: "running", Disabled: true, Running: true}, []string{"running", "disabled"}},
+		{JobSummary{Status: "disabled", Disabled: true, LastBuildResult: "UNKNOWN"}, []string{"disabled"}},
+	}
+	for _, test := range tests {
+		if got := test.summary.filterStatuses(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("filterStatuses(%+v) = %v, want %v", test.summary, got, test.want)
+		}
+	}
+}
+
+func TestStatusFilterMatch(t *testing.T) {
+	failedDisabled := Job{Result: "FAILURE", Disabled: true}
+	success := Job{Result: "SUCCESS"}
+	tests := []struct {
+		value string
+		job   Job
+		want  bool
+	}{
+		{"all", success, true},
+		{"failure", failedDisabled, true},
+		{"disabled", failedDisabled, true},
+		{"success", failedDisabled, false},
+		{"!disabled", failedDisabled, false},
+		{"failure,!disabled", failedDisabled, false},
+		{"!failure", success, true},
+		{"!success", success, false},
+	}
+	for _, test := range tests {
+		filter, err := ParseStatusFilter(test.value)
+		if err != nil {
+			t.Fatalf("ParseStatusFilter(%q) error = %v", test.value, err)
+		}
+		job := test.job
+		if got := job.checkJobStatusMatch(filter); got != test.want {
+			t.Errorf("checkJobStatusMatch(%q, %+v) = %v, want %v", test.value, test.job, got, test.want)
+		}
+		if got := filter.MatchSummary(&JobSummary{
+			Status: job.Status(), Disabled: job.Disabled, LastBuildResult: job.Result,
+		}); got != test.want {
+			t.Errorf("MatchSummary(%q, %+v) = %v, want %v", test.value, test.job, got, test.want)
+		}
+	}
+}
@@ -16,25 +16,51 @@ limitations under the License.
 
 package jobs
 
-import (
-	"fmt"
-	"jenkinsctl/pkg/apiclient"
-)
+import "jenkinsctl/pkg/apiclient"
 
-func (jobs *Jobs) Stop(clt *apiclient.ApiClient, force bool) error {
-	fmt.Println("Stopping jobs...")
-	for _, job := range jobs.Jobs {
+// StopJobs stops the last build of the running jobs, and returns the outcome
+// for each job until an error occurs. On a dry run, the builds are not
+// stopped, the server dry run checking that they are still running.
+func (jobs *Jobs) StopJobs(clt *apiclient.ApiClient, dryRun string) (results []ActionResult, err error) {
+	defer func() { jobs.recordAudit(clt, "stop", dryRun, results, err) }()
+	results = []ActionResult{}
+	for i := range jobs.Jobs {
+		job := &jobs.Jobs[i]
 		if !job.IsRunning {
-			fmt.Printf("job %s is already in stopped state\n", job.Name)
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_SKIPPED, "job %s is already in stopped state", job.Name,
+			))
+			continue
+		}
+		if dryRun != DRY_RUN_NONE {
+			results = append(results, job.planStop(clt, dryRun))
 			continue
 		}
 		isStopped, err := job.JenkinsLastBuild.Stop(clt.Ctx)
 		if err != nil {
-			return err
+			return results, err
 		}
 		if isStopped {
-			fmt.Printf("job %s is now in stopped state\n", job.Name)
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_DONE, "job %s is now in stopped state", job.Name,
+			))
+		} else {
+			results = append(results, newActionResult(
+				job, ACTION_STATUS_FAILED, "job %s could not be stopped", job.Name,
+			))
 		}
 	}
-	return nil
+	return results, nil
+}
+
+func (job *Job) planStop(clt *apiclient.ApiClient, dryRun string) ActionResult {
+	number := job.JenkinsLastBuild.GetBuildNumber()
+	if dryRun == DRY_RUN_SERVER && !job.JenkinsLastBuild.IsRunning(clt.Ctx) {
+		return newActionResult(
+			job, ACTION_STATUS_SKIPPED, "build #%d of job %s is no longer running", number, job.Name,
+		)
+	}
+	return newActionResult(
+		job, ACTION_STATUS_PLANNED, "job %s would be stopped (build #%d)", job.Name, number,
+	)
 }
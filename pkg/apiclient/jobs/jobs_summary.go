@@ -0,0 +1,101 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import "time"
+
+// JobSummary is the state of a job and of its last build, the fields of the
+// last build being empty for the jobs without build and the duration being
+// given in seconds. LastBuildCause is the short description of the first
+// cause of the last build, like "Started by timer".
+type JobSummary struct {
+	Name               string     `json:"name"`
+	Status             string     `json:"status"`
+	Disabled           bool       `json:"disabled"`
+	Running            bool       `json:"running"`
+	Url                string     `json:"url"`
+	LastBuildNumber    int64      `json:"lastBuildNumber,omitempty"`
+	LastBuildResult    string     `json:"lastBuildResult,omitempty"`
+	LastBuildTimestamp *time.Time `json:"lastBuildTimestamp,omitempty"`
+	LastBuildDuration  float64    `json:"lastBuildDurationSeconds,omitempty"`
+	LastBuildUrl       string     `json:"lastBuildUrl,omitempty"`
+	LastBuildNode      string     `json:"lastBuildNode,omitempty"`
+	LastBuildCause     string     `json:"lastBuildCause,omitempty"`
+}
+
+func (job *Job) Summary() JobSummary {
+	summary := JobSummary{
+		Name:     job.Name,
+		Status:   job.Status(),
+		Disabled: job.Disabled,
+		Running:  job.IsRunning,
+	}
+	if job.JenkinsJob != nil && job.JenkinsJob.Raw != nil {
+		summary.Url = job.JenkinsJob.Raw.URL
+	}
+	if job.Result != JOB_STATUS_NOBUILD {
+		timestamp := job.LastBuildCreationDate
+		summary.LastBuildNumber = job.lastBuildNumber()
+		summary.LastBuildResult = job.Result
+		summary.LastBuildTimestamp = &timestamp
+		summary.LastBuildDuration = job.lastBuildDuration().Seconds()
+	}
+	if job.JenkinsLastBuild != nil && job.JenkinsLastBuild.Raw != nil {
+		summary.LastBuildUrl = job.JenkinsLastBuild.GetUrl()
+		summary.LastBuildNode = job.JenkinsLastBuild.Raw.BuiltOn
+		summary.LastBuildCause = job.lastBuildCause()
+	}
+	return summary
+}
+
+func (job *Job) lastBuildCause() string {
+	for _, action := range job.JenkinsLastBuild.Raw.Actions {
+		for _, cause := range action.Causes {
+			if description, ok := cause["shortDescription"].(string); ok {
+				return description
+			}
+		}
+	}
+	return ""
+}
+
+// lastBuildDate returns the start date of the last build, the zero time for
+// the jobs without build.
+func (summary *JobSummary) lastBuildDate() time.Time {
+	if summary.LastBuildTimestamp == nil {
+		return time.Time{}
+	}
+	return *summary.LastBuildTimestamp
+}
+
+// Summaries returns the summary of each job.
+func (jobs *Jobs) Summaries() []JobSummary {
+	summaries := make([]JobSummary, len(jobs.Jobs))
+	for i := range jobs.Jobs {
+		summaries[i] = jobs.Jobs[i].Summary()
+	}
+	return summaries
+}
+
+// SummaryNames returns the full names of the jobs.
+func SummaryNames(summaries []JobSummary) []string {
+	names := make([]string, len(summaries))
+	for i := range summaries {
+		names[i] = summaries[i].Name
+	}
+	return names
+}
@@ -0,0 +1,161 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	TEST_STATUS_PASSED     = "PASSED"
+	TEST_STATUS_FIXED      = "FIXED"
+	TEST_STATUS_SKIPPED    = "SKIPPED"
+	TEST_STATUS_FAILED     = "FAILED"
+	TEST_STATUS_REGRESSION = "REGRESSION"
+)
+
+// TestCase is a test of a test report, ErrorDetails being the message of the
+// failure.
+type TestCase struct {
+	ClassName    string
+	Name         string
+	Status       string
+	Duration     float64
+	ErrorDetails string
+}
+
+// TestReport is the test report of a build, as published by the junit step.
+type TestReport struct {
+	Job       string `json:"-"`
+	Number    int64  `json:"-"`
+	FailCount int64
+	PassCount int64
+	SkipCount int64
+	Duration  float64
+	Suites    []struct {
+		Name  string
+		Cases []TestCase
+	}
+}
+
+// FullName returns the class name and the name of the test.
+func (test *TestCase) FullName() string {
+	if test.ClassName == "" {
+		return test.Name
+	}
+	return test.ClassName + "." + test.Name
+}
+
+// Failed reports whether the test failed, a test failing again after a
+// success having the REGRESSION status.
+func (test *TestCase) Failed() bool {
+	return test.Status == TEST_STATUS_FAILED || test.Status == TEST_STATUS_REGRESSION
+}
+
+// Passed reports whether the test passed, a test passing again after a
+// failure having the FIXED status.
+func (test *TestCase) Passed() bool {
+	return test.Status == TEST_STATUS_PASSED || test.Status == TEST_STATUS_FIXED
+}
+
+// GetBuildNumber returns the number of a build of the job located at
+// fullName, the build being given as accepted by ParseBuildRef.
+func GetBuildNumber(clt *apiclient.ApiClient, fullName string, ref string) (int64, error) {
+	build, err := ParseBuildRef(ref)
+	if err != nil {
+		return 0, err
+	}
+	var response struct {
+		Number int64
+	}
+	_, err = clt.Jenkins.Requester.GetJSON(
+		clt.Ctx, JobBase(fullName)+"/"+build, &response, map[string]string{"tree": "number"},
+	)
+	if err != nil {
+		return 0, err
+	}
+	if response.Number == 0 {
+		return 0, notFoundError("build %s of job %s not found", build, fullName)
+	}
+	return response.Number, nil
+}
+
+// GetTestReport returns the test report of a build of the job located at
+// fullName, or the "404" error when the build has no test report.
+func GetTestReport(clt *apiclient.ApiClient, fullName string, number int64) (*TestReport, error) {
+	report := &TestReport{}
+	response, err := clt.Jenkins.Requester.GetJSON(
+		clt.Ctx, JobBase(fullName)+"/"+strconv.FormatInt(number, 10)+"/testReport", report,
+		map[string]string{
+			"tree": "failCount,passCount,skipCount,duration,suites[name,cases[className,name,status,duration,errorDetails]]",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != 200 {
+		return nil, errors.New(strconv.Itoa(response.StatusCode))
+	}
+	report.Job = fullName
+	report.Number = number
+	return report, nil
+}
+
+// FailedTests returns the tests which failed.
+func (report *TestReport) FailedTests() []TestCase {
+	failed := []TestCase{}
+	for _, suite := range report.Suites {
+		for _, test := range suite.Cases {
+			if test.Failed() {
+				failed = append(failed, test)
+			}
+		}
+	}
+	return failed
+}
+
+// PrintTestReport prints the counts of the report and the failed tests with
+// the first line of their error message.
+func (report *TestReport) PrintTestReport() {
+	fmt.Printf(
+		"%s #%d: %d passed, %d failed, %d skipped, duration %s\n",
+		report.Job, report.Number, report.PassCount, report.FailCount, report.SkipCount,
+		(time.Duration(report.Duration * float64(time.Second))).Round(time.Millisecond),
+	)
+	failed := report.FailedTests()
+	if len(failed) == 0 {
+		return
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Test", "Status", "Error"})
+	for _, test := range failed {
+		message := strings.TrimSpace(test.ErrorDetails)
+		if i := strings.Index(message, "\n"); i >= 0 {
+			message = message[:i]
+		}
+		table.Append([]string{test.FullName(), strings.ToLower(test.Status), message})
+	}
+	table.Render()
+}
@@ -0,0 +1,252 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"jenkinsctl/pkg/apiclient"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// TestOutcome is the status of a test in a build.
+type TestOutcome struct {
+	Build  int64  `json:"build"`
+	Status string `json:"status"`
+}
+
+// FlakyTest is a test whose outcome flipped between passed and failed in the
+// analyzed builds, Rate being the number of flips divided by the number of
+// consecutive runs.
+type FlakyTest struct {
+	ClassName string        `json:"className"`
+	Name      string        `json:"name"`
+	Runs      int           `json:"runs"`
+	Failures  int           `json:"failures"`
+	Flips     int           `json:"flips"`
+	Rate      float64       `json:"rate"`
+	Outcomes  []TestOutcome `json:"outcomes"`
+}
+
+// FlakyTests are the flaky tests of a job, the most flaky first.
+type FlakyTests struct {
+	Job    string      `json:"job"`
+	Builds []int64     `json:"builds"`
+	Tests  []FlakyTest `json:"tests"`
+}
+
+// getLastBuildNumbers returns the numbers of the last builds of the job, the
+// most recent first.
+func getLastBuildNumbers(clt *apiclient.ApiClient, fullName string, count int) ([]int64, error) {
+	var response struct {
+		AllBuilds []struct {
+			Number int64
+		}
+	}
+	_, err := clt.Jenkins.Requester.GetJSON(
+		clt.Ctx, JobBase(fullName), &response,
+		map[string]string{"tree": fmt.Sprintf("allBuilds[number]{0,%d}", count)},
+	)
+	if err != nil {
+		return nil, err
+	}
+	numbers := []int64{}
+	for _, build := range response.AllBuilds {
+		numbers = append(numbers, build.Number)
+	}
+	if len(numbers) > count {
+		numbers = numbers[:count]
+	}
+	return numbers, nil
+}
+
+// GetFlakyTests analyzes the test reports of the last builds of the job
+// located at fullName, the builds without test report being ignored.
+func GetFlakyTests(clt *apiclient.ApiClient, fullName string, count int) (*FlakyTests, error) {
+	numbers, err := getLastBuildNumbers(clt, fullName, count)
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]*TestReport, len(numbers))
+	errs := make([]error, len(numbers))
+	clt.RunConcurrently(len(numbers), func(i int) {
+		reports[i], errs[i] = GetTestReport(clt, fullName, numbers[i])
+	})
+
+	flaky := &FlakyTests{Job: fullName, Builds: []int64{}, Tests: []FlakyTest{}}
+	tests := map[string]*FlakyTest{}
+	names := []string{}
+	// The outcomes are gathered from the oldest build to the most recent.
+	for i := len(numbers) - 1; i >= 0; i-- {
+		if errs[i] != nil {
+			if errs[i].Error() == "404" {
+				continue
+			}
+			return nil, errs[i]
+		}
+		flaky.Builds = append(flaky.Builds, numbers[i])
+		for _, suite := range reports[i].Suites {
+			for _, test := range suite.Cases {
+				if !test.Passed() && !test.Failed() {
+					continue
+				}
+				name := test.FullName()
+				if _, ok := tests[name]; !ok {
+					tests[name] = &FlakyTest{ClassName: test.ClassName, Name: test.Name}
+					names = append(names, name)
+				}
+				status := TEST_STATUS_PASSED
+				if test.Failed() {
+					status = TEST_STATUS_FAILED
+				}
+				tests[name].Outcomes = append(tests[name].Outcomes, TestOutcome{numbers[i], status})
+			}
+		}
+	}
+
+	for _, name := range names {
+		test := tests[name]
+		test.Runs = len(test.Outcomes)
+		for j, outcome := range test.Outcomes {
+			if outcome.Status == TEST_STATUS_FAILED {
+				test.Failures++
+			}
+			if j > 0 && outcome.Status != test.Outcomes[j-1].Status {
+				test.Flips++
+			}
+		}
+		if test.Flips > 0 {
+			test.Rate = float64(test.Flips) / float64(test.Runs-1)
+			flaky.Tests = append(flaky.Tests, *test)
+		}
+	}
+	sort.SliceStable(flaky.Tests, func(i, j int) bool {
+		if flaky.Tests[i].Rate != flaky.Tests[j].Rate {
+			return flaky.Tests[i].Rate > flaky.Tests[j].Rate
+		}
+		return flaky.Tests[i].Flips > flaky.Tests[j].Flips
+	})
+	return flaky, nil
+}
+
+// history returns the outcomes of the test as a string of P (passed) and F
+// (failed), from the oldest build to the most recent.
+func (test *FlakyTest) history() string {
+	history := ""
+	for _, outcome := range test.Outcomes {
+		history += outcome.Status[:1]
+	}
+	return history
+}
+
+func (test *FlakyTest) fullName() string {
+	testCase := TestCase{ClassName: test.ClassName, Name: test.Name}
+	return testCase.FullName()
+}
+
+func (flaky *FlakyTests) PrintFlakyTestsTable() {
+	fmt.Printf("%s: %d flaky tests in %d builds with a test report\n", flaky.Job, len(flaky.Tests), len(flaky.Builds))
+	if len(flaky.Tests) == 0 {
+		return
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Test", "Rate", "Flips", "Failures", "Runs", "History"})
+	for i := range flaky.Tests {
+		test := &flaky.Tests[i]
+		table.Append([]string{
+			test.fullName(),
+			fmt.Sprintf("%.0f%%", test.Rate*100),
+			strconv.Itoa(test.Flips),
+			strconv.Itoa(test.Failures),
+			strconv.Itoa(test.Runs),
+			test.history(),
+		})
+	}
+	table.Render()
+}
+
+func (flaky *FlakyTests) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(flaky)
+}
+
+// WriteJUnit writes the flaky tests as a JUnit XML report, each flaky test
+// being a failed test case so that CI servers show them.
+func (flaky *FlakyTests) WriteJUnit(writer io.Writer) error {
+	type failure struct {
+		Type    string `xml:"type,attr"`
+		Message string `xml:"message,attr"`
+		Text    string `xml:",chardata"`
+	}
+	type testCase struct {
+		ClassName string  `xml:"classname,attr"`
+		Name      string  `xml:"name,attr"`
+		Failure   failure `xml:"failure"`
+	}
+	type testSuite struct {
+		XMLName  xml.Name   `xml:"testsuite"`
+		Name     string     `xml:"name,attr"`
+		Tests    int        `xml:"tests,attr"`
+		Failures int        `xml:"failures,attr"`
+		Cases    []testCase `xml:"testcase"`
+	}
+
+	suite := testSuite{
+		Name:     flaky.Job + " flaky tests",
+		Tests:    len(flaky.Tests),
+		Failures: len(flaky.Tests),
+		Cases:    []testCase{},
+	}
+	for i := range flaky.Tests {
+		test := &flaky.Tests[i]
+		outcomes := make([]string, len(test.Outcomes))
+		for j, outcome := range test.Outcomes {
+			outcomes[j] = fmt.Sprintf("#%d %s", outcome.Build, strings.ToLower(outcome.Status))
+		}
+		suite.Cases = append(suite.Cases, testCase{
+			ClassName: test.ClassName,
+			Name:      test.Name,
+			Failure: failure{
+				Type: "flaky",
+				Message: fmt.Sprintf(
+					"flaky test: %d flips in %d runs (%.0f%%), %d failures",
+					test.Flips, test.Runs, test.Rate*100, test.Failures,
+				),
+				Text: strings.Join(outcomes, ", "),
+			},
+		})
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suite); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
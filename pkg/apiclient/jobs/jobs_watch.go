@@ -0,0 +1,68 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import "fmt"
+
+// JobStatusChange is a change of the status of a job between two listings,
+// From is empty for the new jobs and To is empty for the removed jobs.
+type JobStatusChange struct {
+	Name string
+	From string
+	To   string
+}
+
+func (change JobStatusChange) String() string {
+	switch {
+	case change.From == "":
+		return fmt.Sprintf("job %s: %s", change.Name, change.To)
+	case change.To == "":
+		return fmt.Sprintf("job %s: removed", change.Name)
+	default:
+		return fmt.Sprintf("job %s: %s -> %s", change.Name, change.From, change.To)
+	}
+}
+
+// StatusChanges returns the changes of status since the previous listing,
+// in the order of the jobs followed by the removed jobs. Every job is new
+// when previous is empty.
+func StatusChanges(summaries []JobSummary, previous []JobSummary) []JobStatusChange {
+	previousStatuses := map[string]string{}
+	for i := range previous {
+		previousStatuses[previous[i].Name] = previous[i].Status
+	}
+
+	changes := []JobStatusChange{}
+	seen := map[string]bool{}
+	for i := range summaries {
+		job := &summaries[i]
+		seen[job.Name] = true
+		if job.Status != previousStatuses[job.Name] {
+			changes = append(changes, JobStatusChange{
+				Name: job.Name, From: previousStatuses[job.Name], To: job.Status,
+			})
+		}
+	}
+	for i := range previous {
+		if name := previous[i].Name; !seen[name] {
+			changes = append(changes, JobStatusChange{
+				Name: name, From: previousStatuses[name],
+			})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,194 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"sort"
+	"time"
+
+	"github.com/bndr/gojenkins"
+)
+
+const (
+	TREND_IMPROVING = "improving"
+	TREND_DEGRADING = "degrading"
+	TREND_FASTER    = "faster"
+	TREND_SLOWER    = "slower"
+	TREND_STABLE    = "stable"
+	TREND_UNKNOWN   = ""
+)
+
+// The trend is stable while the success rate of the recent builds differs
+// by less than trendRateThreshold from the one of the older builds, and
+// while their mean duration differs by less than trendDurationThreshold.
+const (
+	trendRateThreshold     = 0.05
+	trendDurationThreshold = 0.10
+)
+
+// JobTrend gives the statistics of the builds of a job over the window, the
+// builds still running and the aborted ones being ignored.
+type JobTrend struct {
+	Name          string
+	Builds        int
+	Successes     int
+	SuccessRate   float64
+	LastDuration  time.Duration
+	MeanDuration  time.Duration
+	P50Duration   time.Duration
+	P95Duration   time.Duration
+	MTTR          time.Duration
+	Recoveries    int
+	SuccessTrend  string
+	DurationTrend string
+	Durations     []time.Duration
+	Results       []string
+}
+
+// GetTrends computes the trends of the jobs from their last builds started
+// during the window before now.
+func GetTrends(
+	clt *apiclient.ApiClient, jobList *jobs.Jobs, builds int, window time.Duration, now time.Time,
+) ([]JobTrend, error) {
+	trends := make([]JobTrend, len(jobList.Jobs))
+	errs := make([]error, len(jobList.Jobs))
+	clt.RunConcurrently(len(jobList.Jobs), func(i int) {
+		job := &jobList.Jobs[i]
+		records, err := jobs.GetBuildRecords(clt, job.Name, builds)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		trends[i] = ComputeTrend(job.Name, records, now.Add(-window), now)
+		trends[i].LastDuration = time.Duration(job.LastBuildDuration) * time.Millisecond
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return trends, nil
+}
+
+// ComputeTrend computes the statistics of the builds, given the most recent
+// first, which started between since and until.
+func ComputeTrend(name string, records []jobs.BuildRecord, since time.Time, until time.Time) JobTrend {
+	trend := JobTrend{Name: name}
+
+	// The builds are analyzed from the oldest to the most recent.
+	completed := []jobs.BuildRecord{}
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if record.Building || record.Result == gojenkins.STATUS_ABORTED || record.Result == "" ||
+			record.Timestamp.Before(since) || record.Timestamp.After(until) {
+			continue
+		}
+		completed = append(completed, record)
+	}
+	trend.Builds = len(completed)
+	if trend.Builds == 0 {
+		return trend
+	}
+
+	var total time.Duration
+	var failedSince time.Time
+	var recovery time.Duration
+	for _, record := range completed {
+		success := record.Result == gojenkins.STATUS_SUCCESS
+		if success {
+			trend.Successes++
+		}
+		total += record.Duration
+		trend.Durations = append(trend.Durations, record.Duration)
+		trend.Results = append(trend.Results, record.Result)
+
+		// The time to recover goes from the start of the first failed build
+		// to the end of the next successful one.
+		switch {
+		case !success && failedSince.IsZero():
+			failedSince = record.Timestamp
+		case success && !failedSince.IsZero():
+			recovery += record.Timestamp.Add(record.Duration).Sub(failedSince)
+			trend.Recoveries++
+			failedSince = time.Time{}
+		}
+	}
+	trend.SuccessRate = float64(trend.Successes) / float64(trend.Builds)
+	trend.MeanDuration = total / time.Duration(trend.Builds)
+	if trend.Recoveries > 0 {
+		trend.MTTR = recovery / time.Duration(trend.Recoveries)
+	}
+
+	sorted := append([]time.Duration{}, trend.Durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	trend.P50Duration = percentile(sorted, 50)
+	trend.P95Duration = percentile(sorted, 95)
+
+	trend.SuccessTrend, trend.DurationTrend = directions(completed)
+	return trend
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// directions compares the older half of the builds with the recent half, at
+// least 4 builds being needed to get a trend.
+func directions(completed []jobs.BuildRecord) (string, string) {
+	if len(completed) < 4 {
+		return TREND_UNKNOWN, TREND_UNKNOWN
+	}
+	half := len(completed) / 2
+	olderRate, olderMean := rateAndMean(completed[:half])
+	recentRate, recentMean := rateAndMean(completed[len(completed)-half:])
+
+	successTrend := TREND_STABLE
+	switch {
+	case recentRate-olderRate >= trendRateThreshold:
+		successTrend = TREND_IMPROVING
+	case olderRate-recentRate >= trendRateThreshold:
+		successTrend = TREND_DEGRADING
+	}
+	durationTrend := TREND_STABLE
+	switch {
+	case olderMean == 0:
+	case float64(recentMean-olderMean) >= trendDurationThreshold*float64(olderMean):
+		durationTrend = TREND_SLOWER
+	case float64(olderMean-recentMean) >= trendDurationThreshold*float64(olderMean):
+		durationTrend = TREND_FASTER
+	}
+	return successTrend, durationTrend
+}
+
+func rateAndMean(records []jobs.BuildRecord) (float64, time.Duration) {
+	var successes int
+	var total time.Duration
+	for _, record := range records {
+		if record.Result == gojenkins.STATUS_SUCCESS {
+			successes++
+		}
+		total += record.Duration
+	}
+	return float64(successes) / float64(len(records)), total / time.Duration(len(records))
+}
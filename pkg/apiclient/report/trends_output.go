@@ -0,0 +1,207 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bndr/gojenkins"
+	"github.com/olekukonko/tablewriter"
+)
+
+var trendHeaders = []string{
+	"Name", "Builds", "Success rate", "Last duration", "Mean duration",
+	"P50 duration", "P95 duration", "MTTR", "Success trend", "Duration trend",
+}
+
+func formatDuration(duration time.Duration) string {
+	if duration == 0 {
+		return ""
+	}
+	return duration.Round(time.Second).String()
+}
+
+func (trend *JobTrend) successRate() string {
+	if trend.Builds == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%%", trend.SuccessRate*100)
+}
+
+// row returns the values of trendHeaders, the MTTR being empty when the job
+// did not recover from any failure.
+func (trend *JobTrend) row() []string {
+	return []string{
+		trend.Name,
+		strconv.Itoa(trend.Builds),
+		trend.successRate(),
+		formatDuration(trend.LastDuration),
+		formatDuration(trend.MeanDuration),
+		formatDuration(trend.P50Duration),
+		formatDuration(trend.P95Duration),
+		formatDuration(trend.MTTR),
+		trend.SuccessTrend,
+		trend.DurationTrend,
+	}
+}
+
+func WriteTrendsTable(writer io.Writer, trends []JobTrend) error {
+	table := tablewriter.NewWriter(writer)
+	table.SetHeader(trendHeaders)
+	for i := range trends {
+		table.Append(trends[i].row())
+	}
+	table.Render()
+	return nil
+}
+
+// WriteTrendsCSV writes the trends as CSV, the durations being given in
+// seconds and the success rate as a ratio so that they can be computed on.
+func WriteTrendsCSV(writer io.Writer, trends []JobTrend) error {
+	seconds := func(duration time.Duration) string {
+		return strconv.FormatFloat(duration.Seconds(), 'f', 0, 64)
+	}
+	csvWriter := csv.NewWriter(writer)
+	headers := []string{
+		"name", "builds", "success_rate", "last_duration_seconds", "mean_duration_seconds",
+		"p50_duration_seconds", "p95_duration_seconds", "mttr_seconds", "success_trend", "duration_trend",
+	}
+	if err := csvWriter.Write(headers); err != nil {
+		return err
+	}
+	for _, trend := range trends {
+		err := csvWriter.Write([]string{
+			trend.Name,
+			strconv.Itoa(trend.Builds),
+			strconv.FormatFloat(trend.SuccessRate, 'f', 3, 64),
+			seconds(trend.LastDuration),
+			seconds(trend.MeanDuration),
+			seconds(trend.P50Duration),
+			seconds(trend.P95Duration),
+			seconds(trend.MTTR),
+			trend.SuccessTrend,
+			trend.DurationTrend,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+const (
+	sparklineWidth  = 160
+	sparklineHeight = 24
+)
+
+// sparkline draws the durations of the builds as an inline SVG, the failed
+// builds being marked with a red dot.
+func (trend *JobTrend) sparkline() template.HTML {
+	if len(trend.Durations) == 0 {
+		return ""
+	}
+	var max time.Duration
+	for _, duration := range trend.Durations {
+		if duration > max {
+			max = duration
+		}
+	}
+	step := 0.0
+	if len(trend.Durations) > 1 {
+		step = float64(sparklineWidth-4) / float64(len(trend.Durations)-1)
+	}
+	points := make([]string, len(trend.Durations))
+	dots := ""
+	for i, duration := range trend.Durations {
+		x := 2 + step*float64(i)
+		y := float64(sparklineHeight - 2)
+		if max > 0 {
+			y -= float64(sparklineHeight-4) * float64(duration) / float64(max)
+		}
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+		if trend.Results[i] != gojenkins.STATUS_SUCCESS {
+			dots += fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="2" fill="#d9534f"/>`, x, y)
+		}
+	}
+	return template.HTML(fmt.Sprintf(
+		`<svg width="%d" height="%d" viewBox="0 0 %d %d"><polyline fill="none" stroke="#337ab7" stroke-width="1.5" points="%s"/>%s</svg>`,
+		sparklineWidth, sparklineHeight, sparklineWidth, sparklineHeight, strings.Join(points, " "), dots,
+	))
+}
+
+var trendsTemplate = template.Must(template.New("trends").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Jenkins build trends</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #333; }
+table { border-collapse: collapse; }
+th, td { padding: 4px 10px; border-bottom: 1px solid #ddd; text-align: left; white-space: nowrap; }
+th { background: #f5f5f5; }
+td.number { text-align: right; }
+.degrading, .slower { color: #d9534f; }
+.improving, .faster { color: #5cb85c; }
+</style>
+</head>
+<body>
+<h1>Jenkins build trends</h1>
+<p>Builds started between {{.Since}} and {{.Until}}, the durations being drawn from the oldest build to the most recent and the failed builds marked in red.</p>
+<table>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}<th>Durations</th></tr>
+{{range .Trends}}<tr>
+<td>{{.Name}}</td><td class="number">{{.Builds}}</td><td class="number">{{index .Row 2}}</td>
+<td class="number">{{index .Row 3}}</td><td class="number">{{index .Row 4}}</td><td class="number">{{index .Row 5}}</td>
+<td class="number">{{index .Row 6}}</td><td class="number">{{index .Row 7}}</td>
+<td class="{{.SuccessTrend}}">{{.SuccessTrend}}</td><td class="{{.DurationTrend}}">{{.DurationTrend}}</td>
+<td>{{.Sparkline}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// WriteTrendsHTML writes the trends as a self-contained HTML page, with a
+// sparkline of the durations of the builds of each job.
+func WriteTrendsHTML(writer io.Writer, trends []JobTrend, since time.Time, until time.Time) error {
+	type htmlTrend struct {
+		JobTrend
+		Row       []string
+		Sparkline template.HTML
+	}
+	data := struct {
+		Since   string
+		Until   string
+		Headers []string
+		Trends  []htmlTrend
+	}{
+		Since:   since.Format("2006-01-02 15:04"),
+		Until:   until.Format("2006-01-02 15:04"),
+		Headers: trendHeaders,
+	}
+	for i := range trends {
+		data.Trends = append(data.Trends, htmlTrend{trends[i], trends[i].row(), trends[i].sparkline()})
+	}
+	return trendsTemplate.Execute(writer, data)
+}
@@ -0,0 +1,196 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"jenkinsctl/pkg/apiclient/jobs"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var trendStart = time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+
+// testBuild is a build started minutes after trendStart and lasting duration
+// minutes.
+type testBuild struct {
+	result   string
+	minutes  int
+	duration int
+}
+
+// records returns the records of the builds, given the oldest first, in the
+// order of GetBuildRecords, the most recent first.
+func records(builds ...testBuild) []jobs.BuildRecord {
+	result := make([]jobs.BuildRecord, len(builds))
+	for i, build := range builds {
+		result[len(builds)-1-i] = jobs.BuildRecord{
+			Number:    int64(i + 1),
+			Result:    build.result,
+			Timestamp: trendStart.Add(time.Duration(build.minutes) * time.Minute),
+			Duration:  time.Duration(build.duration) * time.Minute,
+		}
+	}
+	return result
+}
+
+func TestComputeTrend(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []jobs.BuildRecord
+		want    JobTrend
+	}{
+		{
+			name:    "no build",
+			records: nil,
+			want:    JobTrend{},
+		},
+		{
+			name:    "single build",
+			records: records(testBuild{"SUCCESS", 0, 3}),
+			want: JobTrend{
+				Builds: 1, Successes: 1, SuccessRate: 1,
+				MeanDuration: 3 * time.Minute, P50Duration: 3 * time.Minute, P95Duration: 3 * time.Minute,
+				Durations: []time.Duration{3 * time.Minute}, Results: []string{"SUCCESS"},
+			},
+		},
+		{
+			name: "no success",
+			records: records(
+				testBuild{"FAILURE", 0, 1}, testBuild{"UNSTABLE", 10, 2}, testBuild{"FAILURE", 20, 3},
+			),
+			want: JobTrend{
+				Builds: 3, Successes: 0, SuccessRate: 0,
+				MeanDuration: 2 * time.Minute, P50Duration: 2 * time.Minute, P95Duration: 3 * time.Minute,
+				Durations: []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute},
+				Results:   []string{"FAILURE", "UNSTABLE", "FAILURE"},
+			},
+		},
+		{
+			// The recovery goes from the start of the first failure, at 10,
+			// to the end of the next success, at 32. The last failures are
+			// not recovered yet.
+			name: "open failure streak",
+			records: records(
+				testBuild{"SUCCESS", 0, 2}, testBuild{"FAILURE", 10, 2}, testBuild{"FAILURE", 20, 2},
+				testBuild{"SUCCESS", 30, 2}, testBuild{"FAILURE", 40, 2}, testBuild{"FAILURE", 50, 2},
+			),
+			want: JobTrend{
+				Builds: 6, Successes: 2, SuccessRate: 2.0 / 6,
+				MeanDuration: 2 * time.Minute, P50Duration: 2 * time.Minute, P95Duration: 2 * time.Minute,
+				MTTR: 22 * time.Minute, Recoveries: 1,
+				SuccessTrend: TREND_STABLE, DurationTrend: TREND_STABLE,
+				Durations: []time.Duration{
+					2 * time.Minute, 2 * time.Minute, 2 * time.Minute, 2 * time.Minute, 2 * time.Minute, 2 * time.Minute,
+				},
+				Results: []string{"SUCCESS", "FAILURE", "FAILURE", "SUCCESS", "FAILURE", "FAILURE"},
+			},
+		},
+		{
+			name: "improving and faster",
+			records: records(
+				testBuild{"FAILURE", 0, 10}, testBuild{"FAILURE", 20, 10},
+				testBuild{"SUCCESS", 40, 5}, testBuild{"SUCCESS", 60, 5},
+			),
+			want: JobTrend{
+				Builds: 4, Successes: 2, SuccessRate: 0.5,
+				MeanDuration: 7*time.Minute + 30*time.Second, P50Duration: 5 * time.Minute, P95Duration: 10 * time.Minute,
+				MTTR: 45 * time.Minute, Recoveries: 1,
+				SuccessTrend: TREND_IMPROVING, DurationTrend: TREND_FASTER,
+				Durations: []time.Duration{10 * time.Minute, 10 * time.Minute, 5 * time.Minute, 5 * time.Minute},
+				Results:   []string{"FAILURE", "FAILURE", "SUCCESS", "SUCCESS"},
+			},
+		},
+		{
+			name: "degrading",
+			records: records(
+				testBuild{"SUCCESS", 0, 4}, testBuild{"SUCCESS", 20, 4},
+				testBuild{"FAILURE", 40, 4}, testBuild{"FAILURE", 60, 4},
+			),
+			want: JobTrend{
+				Builds: 4, Successes: 2, SuccessRate: 0.5,
+				MeanDuration: 4 * time.Minute, P50Duration: 4 * time.Minute, P95Duration: 4 * time.Minute,
+				SuccessTrend: TREND_DEGRADING, DurationTrend: TREND_STABLE,
+				Durations: []time.Duration{4 * time.Minute, 4 * time.Minute, 4 * time.Minute, 4 * time.Minute},
+				Results:   []string{"SUCCESS", "SUCCESS", "FAILURE", "FAILURE"},
+			},
+		},
+		{
+			// With an odd count, the middle build belongs to no half.
+			name: "stable and slower",
+			records: records(
+				testBuild{"SUCCESS", 0, 10}, testBuild{"SUCCESS", 20, 10}, testBuild{"FAILURE", 40, 1},
+				testBuild{"SUCCESS", 60, 11}, testBuild{"SUCCESS", 80, 11},
+			),
+			want: JobTrend{
+				Builds: 5, Successes: 4, SuccessRate: 0.8,
+				MeanDuration: 8*time.Minute + 36*time.Second, P50Duration: 10 * time.Minute, P95Duration: 11 * time.Minute,
+				MTTR: 31 * time.Minute, Recoveries: 1,
+				SuccessTrend: TREND_STABLE, DurationTrend: TREND_SLOWER,
+				Durations: []time.Duration{
+					10 * time.Minute, 10 * time.Minute, time.Minute, 11 * time.Minute, 11 * time.Minute,
+				},
+				Results: []string{"SUCCESS", "SUCCESS", "FAILURE", "SUCCESS", "SUCCESS"},
+			},
+		},
+	}
+	for _, test := range tests {
+		got := ComputeTrend("app", test.records, trendStart, trendStart.Add(24*time.Hour))
+		test.want.Name = "app"
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: ComputeTrend() = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestComputeTrendIgnoredBuilds(t *testing.T) {
+	builds := records(
+		testBuild{"SUCCESS", -10, 1},
+		testBuild{"ABORTED", 10, 1},
+		testBuild{"FAILURE", 20, 4},
+		testBuild{"", 30, 1},
+		testBuild{"SUCCESS", 40, 1},
+	)
+	builds[0].Building = true
+	got := ComputeTrend("app", builds, trendStart, trendStart.Add(time.Hour))
+	if got.Builds != 1 || got.Successes != 0 || got.MeanDuration != 4*time.Minute {
+		t.Errorf("ComputeTrend() = %+v, want only the failed build", got)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{}
+	for i := 1; i <= 10; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Second)
+	}
+	tests := []struct {
+		p    int
+		want time.Duration
+	}{
+		{0, time.Second},
+		{10, time.Second},
+		{50, 5 * time.Second},
+		{51, 6 * time.Second},
+		{95, 10 * time.Second},
+		{100, 10 * time.Second},
+	}
+	for _, test := range tests {
+		if got := percentile(sorted, test.p); got != test.want {
+			t.Errorf("percentile(%d) = %v, want %v", test.p, got, test.want)
+		}
+	}
+}
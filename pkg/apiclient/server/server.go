@@ -0,0 +1,151 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/audit"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Server serves the operations of jenkinsctl as a JSON API, the clients being
+// authenticated by a bearer token and the actions they request being recorded
+// in the audit log.
+type Server struct {
+	clt    *apiclient.ApiClient
+	tokens map[string]string
+	audit  func(entry *audit.Entry)
+}
+
+// ErrorResponse is the body of the responses of the failed requests.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewServer returns a server accepting the tokens, given by client name, and
+// recording the actions run on the jobs with audit.
+func NewServer(clt *apiclient.ApiClient, tokens map[string]string, audit func(entry *audit.Entry)) *Server {
+	return &Server{clt: clt, tokens: tokens, audit: audit}
+}
+
+// LoadTokens reads the tokens of the clients from a file, one "<client>
+// <token>" pair per line, the empty lines and the lines starting with # being
+// ignored.
+func LoadTokens(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tokens := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected a client name and a token", path, line)
+		}
+		if _, ok := tokens[fields[0]]; ok {
+			return nil, fmt.Errorf("%s:%d: client %s defined twice", path, line, fields[0])
+		}
+		tokens[fields[0]] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no token found in %s", path)
+	}
+	return tokens, nil
+}
+
+// authenticate returns the name of the client whose token is given in the
+// Authorization header, or an empty string.
+func (server *Server) authenticate(request *http.Request) string {
+	header := request.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return ""
+	}
+	token := strings.TrimPrefix(header, "Bearer ")
+	client := ""
+	for name, expected := range server.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+			client = name
+		}
+	}
+	return client
+}
+
+// clientKey is the key of the name of the authenticated client in the
+// context of the requests.
+type clientKey struct{}
+
+// Handler returns the handler of the routes of the API.
+func (server *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for i := range routes {
+		route := &routes[i]
+		mux.HandleFunc(route.Path, func(writer http.ResponseWriter, request *http.Request) {
+			server.serveRoute(route, writer, request)
+		})
+	}
+	mux.HandleFunc("/", func(writer http.ResponseWriter, request *http.Request) {
+		writeError(writer, http.StatusNotFound, "%s not found", request.URL.Path)
+	})
+	return mux
+}
+
+func (server *Server) serveRoute(route *route, writer http.ResponseWriter, request *http.Request) {
+	if !route.Public {
+		client := server.authenticate(request)
+		if client == "" {
+			writer.Header().Set("WWW-Authenticate", `Bearer realm="jenkinsctl"`)
+			writeError(writer, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		request = request.WithContext(context.WithValue(request.Context(), clientKey{}, client))
+	}
+	if request.Method != route.Method {
+		writer.Header().Set("Allow", route.Method)
+		writeError(writer, http.StatusMethodNotAllowed, "method %s not allowed", request.Method)
+		return
+	}
+	route.handler(server, writer, request)
+}
+
+func writeJSON(writer http.ResponseWriter, status int, value interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+func writeError(writer http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(writer, status, ErrorResponse{Error: fmt.Sprintf(format, args...)})
+}
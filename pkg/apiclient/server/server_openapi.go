@@ -0,0 +1,210 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+const openAPIVersion = "3.0.3"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf returns the JSON schema of a type, the structs being added to the
+// components and referenced.
+func schemaOf(t reflect.Type, components map[string]interface{}) map[string]interface{} {
+	switch {
+	case t.Kind() == reflect.Ptr:
+		return schemaOf(t.Elem(), components)
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaOf(t.Elem(), components)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaOf(t.Elem(), components)}
+	case reflect.Struct:
+		if _, ok := components[t.Name()]; !ok {
+			// The component is reserved before its fields are walked, so that
+			// the recursive types end.
+			components[t.Name()] = nil
+			properties := map[string]interface{}{}
+			required := []string{}
+			addStructFields(t, properties, &required, components)
+			schema := map[string]interface{}{"type": "object", "properties": properties}
+			if len(required) > 0 {
+				schema["required"] = required
+			}
+			components[t.Name()] = schema
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	}
+	return map[string]interface{}{}
+}
+
+// addStructFields adds the JSON fields of a struct to the properties, the
+// fields of the embedded structs being inlined like encoding/json does.
+func addStructFields(
+	t reflect.Type, properties map[string]interface{}, required *[]string, components map[string]interface{},
+) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" {
+			addStructFields(field.Type, properties, required, components)
+			continue
+		}
+		if field.PkgPath != "" || tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, options = tag[:i], tag[i+1:]
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaOf(field.Type, components)
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func errorResponse(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "#/components/schemas/ErrorResponse"},
+			},
+		},
+	}
+}
+
+// operation returns the OpenAPI operation of a route, generated from its
+// parameters and the types of its bodies.
+func (route *route) operation(components map[string]interface{}) map[string]interface{} {
+	operation := map[string]interface{}{
+		"operationId": route.ID,
+		"summary":     route.Summary,
+	}
+	if route.Public {
+		operation["security"] = []interface{}{}
+	}
+
+	parameters := []interface{}{}
+	for _, parameter := range route.Query {
+		schema := map[string]interface{}{"type": parameter.Type}
+		if parameter.Repeated {
+			schema = map[string]interface{}{"type": "array", "items": schema}
+		}
+		parameters = append(parameters, map[string]interface{}{
+			"name":        parameter.Name,
+			"in":          "query",
+			"description": parameter.Description,
+			"required":    parameter.Required,
+			"schema":      schema,
+		})
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	if route.Body != nil {
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": schemaOf(reflect.TypeOf(route.Body), components),
+				},
+			},
+		}
+	}
+
+	var content map[string]interface{}
+	if route.Stream {
+		// The events are described by the components of their data.
+		for _, event := range route.Response.([]interface{}) {
+			schemaOf(reflect.TypeOf(event), components)
+		}
+		content = map[string]interface{}{
+			"text/event-stream": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+		}
+	} else {
+		content = map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": schemaOf(reflect.TypeOf(route.Response), components),
+			},
+		}
+	}
+	responses := map[string]interface{}{
+		"200": map[string]interface{}{"description": "OK", "content": content},
+	}
+	if !route.Public {
+		responses["400"] = errorResponse("Invalid request")
+		responses["401"] = errorResponse("Missing or invalid token")
+		responses["502"] = errorResponse("Jenkins request failed")
+	}
+	if route.Body != nil || route.Stream {
+		responses["404"] = errorResponse("No job or build found")
+	}
+	operation["responses"] = responses
+	return operation
+}
+
+// OpenAPISpec returns the OpenAPI spec of the API, generated from its routes.
+func OpenAPISpec() map[string]interface{} {
+	components := map[string]interface{}{}
+	schemaOf(reflect.TypeOf(ErrorResponse{}), components)
+	paths := map[string]interface{}{}
+	for i := range routes {
+		route := &routes[i]
+		if _, ok := paths[route.Path]; !ok {
+			paths[route.Path] = map[string]interface{}{}
+		}
+		paths[route.Path].(map[string]interface{})[strings.ToLower(route.Method)] = route.operation(components)
+	}
+	return map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":       "jenkinsctl API",
+			"description": "Operations of jenkinsctl on the jobs of a Jenkins server",
+			"version":     "v1",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": components,
+			"securitySchemes": map[string]interface{}{
+				"token": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []interface{}{map[string]interface{}{"token": []string{}}},
+	}
+}
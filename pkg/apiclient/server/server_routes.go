@@ -0,0 +1,406 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/audit"
+	"jenkinsctl/pkg/selector"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logInterval is the interval between two reads of the console log of a
+// running build.
+const logInterval = 2 * time.Second
+
+type queryParameter struct {
+	Name        string
+	Description string
+	Type        string
+	Repeated    bool
+	Required    bool
+}
+
+// route is an operation of the API, Body and Response being values of the
+// types of the request and response bodies from which the OpenAPI spec is
+// generated. The response of the Stream routes is an event stream.
+type route struct {
+	ID       string
+	Method   string
+	Path     string
+	Summary  string
+	Public   bool
+	Query    []queryParameter
+	Body     interface{}
+	Response interface{}
+	Stream   bool
+	handler  func(server *Server, writer http.ResponseWriter, request *http.Request)
+}
+
+// JobSelection selects jobs like the selection flags of the commands, see
+// the job list command.
+type JobSelection struct {
+	Names    []string `json:"names,omitempty"`
+	Regex    string   `json:"regex,omitempty"`
+	Exclude  []string `json:"exclude,omitempty"`
+	Status   string   `json:"status,omitempty"`
+	Selector string   `json:"selector,omitempty"`
+}
+
+// ActionRequest is the body of the actions, DryRun being a dry run mode of
+// the --dry-run flag of the commands, none by default.
+type ActionRequest struct {
+	JobSelection
+	DryRun string `json:"dryRun,omitempty"`
+}
+
+type ScheduleRequest struct {
+	ActionRequest
+	Schedule string `json:"schedule"`
+}
+
+// ActionResponse gives the outcome of an action for each job, Error being set
+// when the action failed on a job, the next jobs being left untouched.
+type ActionResponse struct {
+	Results []jobs.ActionResult `json:"results"`
+	Error   string              `json:"error,omitempty"`
+}
+
+// LogEvent is the data of the log events of the log stream, the console log
+// being split in chunks of lines.
+type LogEvent struct {
+	Text string `json:"text"`
+}
+
+// LogEndEvent is the data of the end event of the log stream.
+type LogEndEvent struct {
+	Job      string `json:"job"`
+	Build    int64  `json:"build"`
+	Result   string `json:"result"`
+	Building bool   `json:"building"`
+}
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+var selectionParameters = []queryParameter{
+	{Name: "name", Description: "Glob pattern on the full name of the jobs", Type: "string", Repeated: true},
+	{Name: "regex", Description: "Regular expression on the full name of the jobs", Type: "string"},
+	{Name: "exclude", Description: "Glob pattern of the jobs to exclude", Type: "string", Repeated: true},
+	{Name: "status", Description: "Comma separated list of statuses, like failure,unstable or !success", Type: "string"},
+	{Name: "selector", Description: "Selector expression, like 'status in (failure, unstable) && age < 2h'", Type: "string"},
+}
+
+var routes []route
+
+func init() {
+	routes = []route{
+		{
+			ID: "health", Method: http.MethodGet, Path: "/healthz", Summary: "Check that the server is up",
+			Public: true, Response: HealthResponse{}, handler: handleHealth,
+		},
+		{
+			ID: "openapi", Method: http.MethodGet, Path: "/api/v1/openapi.json", Summary: "OpenAPI spec of the API",
+			Public: true, Response: map[string]interface{}{}, handler: handleOpenAPI,
+		},
+		{
+			ID: "listJobs", Method: http.MethodGet, Path: "/api/v1/jobs", Summary: "List the jobs",
+			Query: selectionParameters, Response: []jobs.JobSummary{}, handler: handleListJobs,
+		},
+		{
+			ID: "startJobs", Method: http.MethodPost, Path: "/api/v1/jobs/start",
+			Summary: "Start the selected jobs which are not running",
+			Body:    ActionRequest{}, Response: ActionResponse{}, handler: handleStartJobs,
+		},
+		{
+			ID: "stopJobs", Method: http.MethodPost, Path: "/api/v1/jobs/stop",
+			Summary: "Stop the last build of the selected jobs which are running, the status being ignored",
+			Body:    ActionRequest{}, Response: ActionResponse{}, handler: handleStopJobs,
+		},
+		{
+			ID: "scheduleJobs", Method: http.MethodPost, Path: "/api/v1/jobs/schedule",
+			Summary: "Set the schedule of the selected pipeline jobs, in Jenkins time trigger syntax",
+			Body:    ScheduleRequest{}, Response: ActionResponse{}, handler: handleScheduleJobs,
+		},
+		{
+			ID: "streamLogs", Method: http.MethodGet, Path: "/api/v1/jobs/logs",
+			Summary: "Stream the console log of a build as server-sent events: log events with a LogEvent, " +
+				"then an end event with a LogEndEvent, or an error event with an ErrorResponse",
+			Query: []queryParameter{
+				{Name: "job", Description: "Full name of the job", Type: "string", Required: true},
+				{Name: "build", Description: "Number of the build, or one of " + strings.Join(jobs.BuildRefs, ", "), Type: "string"},
+				{Name: "follow", Description: "Keep streaming the log until the build is over", Type: "boolean"},
+			},
+			Response: []interface{}{LogEvent{}, LogEndEvent{}}, Stream: true, handler: handleStreamLogs,
+		},
+	}
+}
+
+func handleHealth(server *Server, writer http.ResponseWriter, request *http.Request) {
+	writeJSON(writer, http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+func handleOpenAPI(server *Server, writer http.ResponseWriter, request *http.Request) {
+	writeJSON(writer, http.StatusOK, OpenAPISpec())
+}
+
+func (selection *JobSelection) isEmpty() bool {
+	return len(selection.Names) == 0 && selection.Regex == "" && selection.Selector == ""
+}
+
+// filter returns the filter of the selection, status replacing the status of
+// the selection when it is not empty.
+func (selection *JobSelection) filter(status string) (*jobs.JobsFilterParams, error) {
+	filter := &jobs.JobsFilterParams{
+		Names:   selection.Names,
+		Regex:   selection.Regex,
+		Exclude: selection.Exclude,
+		Status:  selection.Status,
+	}
+	if status != "" {
+		filter.Status = status
+	}
+	if filter.Status == "" {
+		filter.Status = jobs.JOB_STATUS_ALL
+	}
+	if selection.Selector != "" {
+		jobSelector, err := selector.Compile(selection.Selector, jobs.SelectorSchema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %s", err)
+		}
+		filter.Selector = jobSelector
+	}
+	return filter, nil
+}
+
+// requestClient returns the client of the server bound to the context of the
+// request, so that the requests to Jenkins are canceled with it, and whose
+// actions are recorded with the client and the route of the request.
+func (server *Server) requestClient(request *http.Request) *apiclient.ApiClient {
+	clt := server.clt.WithContext(request.Context())
+	clt.Audit = nil
+	if server.audit != nil {
+		client, _ := request.Context().Value(clientKey{}).(string)
+		clt.Audit = func(entry *audit.Entry) {
+			entry.Command = request.Method + " " + request.URL.Path
+			entry.Client = client
+			entry.Remote = request.RemoteAddr
+			server.audit(entry)
+		}
+	}
+	return clt
+}
+
+// getJobs returns the jobs of the selection, writing the error response when
+// they can not be listed.
+func getJobs(
+	clt *apiclient.ApiClient, writer http.ResponseWriter, selection *JobSelection, status string,
+) (*jobs.Jobs, bool) {
+	filter, err := selection.filter(status)
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, "%s", err)
+		return nil, false
+	}
+	listed := &jobs.Jobs{}
+	if err := listed.GetFilteredJobs(clt, filter); err != nil {
+		if errors.Is(err, jobs.ErrInvalid) || errors.Is(err, jobs.ErrNotFound) {
+			writeError(writer, http.StatusBadRequest, "%s", err)
+		} else {
+			writeError(writer, http.StatusBadGateway, "%s", err)
+		}
+		return nil, false
+	}
+	return listed, true
+}
+
+func handleListJobs(server *Server, writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	selection := &JobSelection{
+		Names:    query["name"],
+		Regex:    query.Get("regex"),
+		Exclude:  query["exclude"],
+		Status:   query.Get("status"),
+		Selector: query.Get("selector"),
+	}
+	listed, ok := getJobs(server.requestClient(request), writer, selection, "")
+	if !ok {
+		return
+	}
+	writeJSON(writer, http.StatusOK, listed.Summaries())
+}
+
+func decodeBody(writer http.ResponseWriter, request *http.Request, body interface{}) bool {
+	decoder := json.NewDecoder(request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(body); err != nil {
+		writeError(writer, http.StatusBadRequest, "invalid request body: %s", err)
+		return false
+	}
+	return true
+}
+
+// runAction runs an action on the selected jobs, a selection being required
+// so that a request can not act on every job by mistake.
+func (server *Server) runAction(
+	writer http.ResponseWriter, request *http.Request, body *ActionRequest, status string,
+	action func(clt *apiclient.ApiClient, selected *jobs.Jobs, dryRun string) ([]jobs.ActionResult, error),
+) {
+	if body.isEmpty() {
+		writeError(writer, http.StatusBadRequest, "the jobs must be selected by names, regex or selector")
+		return
+	}
+	dryRun := body.DryRun
+	if dryRun == "" {
+		dryRun = jobs.DRY_RUN_NONE
+	}
+	if err := jobs.CheckDryRun(dryRun); err != nil {
+		writeError(writer, http.StatusBadRequest, "%s", err)
+		return
+	}
+	clt := server.requestClient(request)
+	selected, ok := getJobs(clt, writer, &body.JobSelection, status)
+	if !ok {
+		return
+	}
+	if len(selected.Jobs) == 0 {
+		writeError(writer, http.StatusNotFound, "no job matches your rules")
+		return
+	}
+
+	results, err := action(clt, selected, dryRun)
+	if err != nil {
+		writeJSON(writer, http.StatusBadGateway, ActionResponse{Results: results, Error: err.Error()})
+		return
+	}
+	writeJSON(writer, http.StatusOK, ActionResponse{Results: results})
+}
+
+func handleStartJobs(server *Server, writer http.ResponseWriter, request *http.Request) {
+	body := &ActionRequest{}
+	if !decodeBody(writer, request, body) {
+		return
+	}
+	server.runAction(writer, request, body, "",
+		func(clt *apiclient.ApiClient, selected *jobs.Jobs, dryRun string) ([]jobs.ActionResult, error) {
+			return selected.StartJobs(clt, dryRun)
+		},
+	)
+}
+
+func handleStopJobs(server *Server, writer http.ResponseWriter, request *http.Request) {
+	body := &ActionRequest{}
+	if !decodeBody(writer, request, body) {
+		return
+	}
+	server.runAction(writer, request, body, jobs.JOB_STATUS_RUNNING,
+		func(clt *apiclient.ApiClient, selected *jobs.Jobs, dryRun string) ([]jobs.ActionResult, error) {
+			return selected.StopJobs(clt, dryRun)
+		},
+	)
+}
+
+func handleScheduleJobs(server *Server, writer http.ResponseWriter, request *http.Request) {
+	body := &ScheduleRequest{}
+	if !decodeBody(writer, request, body) {
+		return
+	}
+	if strings.TrimSpace(body.Schedule) == "" {
+		writeError(writer, http.StatusBadRequest, "the schedule is required")
+		return
+	}
+	server.runAction(writer, request, &body.ActionRequest, "",
+		func(clt *apiclient.ApiClient, selected *jobs.Jobs, dryRun string) ([]jobs.ActionResult, error) {
+			return selected.ScheduleJobs(clt, body.Schedule, dryRun)
+		},
+	)
+}
+
+func writeEvent(writer http.ResponseWriter, event string, data interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(writer, "event: %s\ndata: %s\n\n", event, encoded); err != nil {
+		return err
+	}
+	if flusher, ok := writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+func handleStreamLogs(server *Server, writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	name := query.Get("job")
+	if name == "" {
+		writeError(writer, http.StatusBadRequest, "the job is required")
+		return
+	}
+	follow := false
+	if value := query.Get("follow"); value != "" {
+		var err error
+		if follow, err = strconv.ParseBool(value); err != nil {
+			writeError(writer, http.StatusBadRequest, "invalid follow %s", value)
+			return
+		}
+	}
+	clt := server.requestClient(request)
+	number, err := jobs.GetBuildNumber(clt, name, query.Get("build"))
+	if err != nil {
+		switch {
+		case errors.Is(err, jobs.ErrInvalid):
+			writeError(writer, http.StatusBadRequest, "%s", err)
+		case errors.Is(err, jobs.ErrNotFound):
+			writeError(writer, http.StatusNotFound, "%s", err)
+		default:
+			writeError(writer, http.StatusBadGateway, "%s", err)
+		}
+		return
+	}
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.Header().Set("X-Accel-Buffering", "no")
+	writer.WriteHeader(http.StatusOK)
+
+	err = jobs.FollowBuildLog(
+		request.Context(), clt, name, number, follow, logInterval,
+		func(text string) error {
+			return writeEvent(writer, "log", LogEvent{Text: text})
+		},
+	)
+	if err != nil && request.Context().Err() != nil {
+		// The client went away.
+		return
+	}
+	if err == nil {
+		var build *jobs.BuildDetails
+		build, err = jobs.GetBuildDetails(clt, name, strconv.FormatInt(number, 10))
+		if err == nil {
+			writeEvent(writer, "end", LogEndEvent{Job: name, Build: number, Result: build.Result, Building: build.Building})
+			return
+		}
+	}
+	writeEvent(writer, "error", ErrorResponse{Error: err.Error()})
+}
@@ -0,0 +1,212 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the actions run on Jenkins in an append-only log of
+// JSON lines, and reads them back.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Outcome is the result of an action on a job.
+type Outcome struct {
+	Job     string `json:"job"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Entry is a line of the audit log.
+type Entry struct {
+	Time        time.Time `json:"time"`
+	OSUser      string    `json:"osUser"`
+	JenkinsUser string    `json:"jenkinsUser"`
+	Controller  string    `json:"controller"`
+	Command     string    `json:"command"`
+	Action      string    `json:"action"`
+	Jobs        []string  `json:"jobs"`
+	Outcomes    []Outcome `json:"outcomes"`
+	Error       string    `json:"error,omitempty"`
+	// Client and Remote are the name and the address of the client of the
+	// API server which requested the action.
+	Client string `json:"client,omitempty"`
+	Remote string `json:"remote,omitempty"`
+}
+
+// Filter selects entries of the audit log, the empty fields matching any
+// entry. Job is a glob pattern matched against the affected jobs, and Status
+// selects the entries with at least one outcome in this status.
+type Filter struct {
+	Since      time.Time
+	Until      time.Time
+	Action     string
+	Job        string
+	User       string
+	Controller string
+	Status     string
+}
+
+// NewEntry returns an entry for the action run now by the current OS user
+// with the command line of the process.
+func NewEntry(action string, jenkinsUser string, controller string) *Entry {
+	return &Entry{
+		Time:        time.Now(),
+		OSUser:      osUser(),
+		JenkinsUser: jenkinsUser,
+		Controller:  controller,
+		Command:     commandLine(os.Args),
+		Action:      action,
+		Jobs:        []string{},
+		Outcomes:    []Outcome{},
+	}
+}
+
+func osUser() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
+
+// commandLine joins the arguments, quoting the ones a shell would split.
+func commandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$*?;&|<>()") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+// DefaultPath returns the path of the audit log in the state directory of the
+// user, $XDG_STATE_HOME or else ~/.local/state.
+func DefaultPath() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateHome, "jenkinsctl", "audit.jsonl"), nil
+}
+
+// Append adds the entry at the end of the audit log, creating the log and its
+// directory when they do not exist. The line is written at once so that the
+// entries of concurrent commands are not interleaved.
+func Append(file string, entry *Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	log, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := log.Write(append(line, '\n')); err != nil {
+		log.Close()
+		return err
+	}
+	return log.Close()
+}
+
+// Read returns the entries of the audit log matching the filter, in the order
+// they were written. A missing log has no entry.
+func Read(file string, filter *Filter) ([]Entry, error) {
+	log, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer log.Close()
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(log)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", file, number, err)
+		}
+		if filter.match(&entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+func (filter *Filter) match(entry *Entry) bool {
+	if !filter.Since.IsZero() && entry.Time.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && entry.Time.After(filter.Until) {
+		return false
+	}
+	if filter.Action != "" && entry.Action != filter.Action {
+		return false
+	}
+	if filter.User != "" && entry.OSUser != filter.User && entry.JenkinsUser != filter.User &&
+		entry.Client != filter.User {
+		return false
+	}
+	if filter.Controller != "" && !strings.Contains(entry.Controller, filter.Controller) {
+		return false
+	}
+	if filter.Job != "" && !entry.hasJob(filter.Job) {
+		return false
+	}
+	if filter.Status != "" && !entry.hasStatus(filter.Status) {
+		return false
+	}
+	return true
+}
+
+func (entry *Entry) hasJob(pattern string) bool {
+	for _, job := range entry.Jobs {
+		if match, _ := path.Match(pattern, job); match {
+			return true
+		}
+	}
+	return false
+}
+
+func (entry *Entry) hasStatus(status string) bool {
+	for _, outcome := range entry.Outcomes {
+		if outcome.Status == status {
+			return true
+		}
+	}
+	return false
+}
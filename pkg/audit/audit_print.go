@@ -0,0 +1,86 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// summary counts the outcomes of the entry by status, like "2 done, 1 failed".
+func (entry *Entry) summary() string {
+	counts := map[string]int{}
+	for _, outcome := range entry.Outcomes {
+		counts[outcome.Status]++
+	}
+	statuses := []string{}
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	parts := []string{}
+	for _, status := range statuses {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+	}
+	if entry.Error != "" {
+		parts = append(parts, "error: "+entry.Error)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// user returns the OS user who ran the action, followed by the client of the
+// API server for the actions requested to it.
+func (entry *Entry) user() string {
+	if entry.Client != "" {
+		return entry.OSUser + "\nclient " + entry.Client
+	}
+	return entry.OSUser
+}
+
+func PrintEntriesTable(writer io.Writer, entries []Entry) {
+	table := tablewriter.NewWriter(writer)
+	table.SetHeader([]string{"Time", "User", "Jenkins user", "Controller", "Action", "Jobs", "Outcome"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	for _, entry := range entries {
+		table.Append([]string{
+			entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.user(),
+			entry.JenkinsUser,
+			entry.Controller,
+			entry.Action,
+			strings.Join(entry.Jobs, "\n"),
+			entry.summary(),
+		})
+	}
+	table.Render()
+}
+
+// WriteEntriesJSON writes the entries as JSON lines, like in the audit log.
+func WriteEntriesJSON(writer io.Writer, entries []Entry) error {
+	encoder := json.NewEncoder(writer)
+	for i := range entries {
+		if err := encoder.Encode(&entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
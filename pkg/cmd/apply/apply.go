@@ -0,0 +1,159 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/apply"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"jenkinsctl/pkg/diff"
+	"jenkinsctl/pkg/manifest"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type ApplyFlags struct {
+	File   string
+	Prune  bool
+	DryRun string
+	Force  bool
+}
+
+func newApplyFlags() *ApplyFlags {
+	return &ApplyFlags{
+		File:   "",
+		Prune:  false,
+		DryRun: jobs.DRY_RUN_NONE,
+		Force:  false,
+	}
+}
+
+func NewApplyCmd(client *apiclient.ApiClient) *cobra.Command {
+	applyFlags := newApplyFlags()
+
+	// cmd represents the apply command
+	var cmd = &cobra.Command{
+		Use:   "apply",
+		Short: "This command allows to manage jobs from YAML manifests",
+		Long: `This command creates and updates the jobs described in YAML manifests
+
+For example:
+	jenkinsctl apply -f jobs.yaml
+	jenkinsctl apply -f jobs/ --dry-run
+	jenkinsctl apply -f jobs/ --prune`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return applyManifests(client, applyFlags)
+		},
+	}
+
+	cmd.Flags().SortFlags = false
+	cmd.Flags().StringVarP(
+		&applyFlags.File, "file", "f", applyFlags.File,
+		"Manifest file, or directory of manifest files",
+	)
+	cmd.Flags().BoolVar(
+		&applyFlags.Prune, "prune", applyFlags.Prune,
+		"Delete the jobs of the managed folders which are not described in the manifests",
+	)
+	cmdutil.AddDryRunFlag(
+		cmd, &applyFlags.DryRun,
+		"Only print the plan without applying it, Jenkins having no validation for the server mode",
+	)
+	cmd.Flags().BoolVar(
+		&applyFlags.Force, "force", applyFlags.Force,
+		"Do not ask for confirmation before applying",
+	)
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func applyManifests(client *apiclient.ApiClient, flags *ApplyFlags) error {
+	manifestJobs, err := manifest.Load(flags.File)
+	if err != nil {
+		return err
+	}
+	if len(manifestJobs) == 0 {
+		return errors.New("no job defined in the manifests")
+	}
+
+	plan, err := apply.ComputePlan(client, manifestJobs, flags.Prune)
+	if err != nil {
+		return err
+	}
+	printPlan(plan)
+	if flags.DryRun != jobs.DRY_RUN_NONE {
+		cmdutil.WarnClientOnlyDryRun(flags.DryRun)
+		return nil
+	}
+	if !plan.HasChanges() {
+		return nil
+	}
+	if !flags.Force {
+		err = cmdutil.AskUserForYesOrNo("apply the changes to")
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Println("Applying changes...")
+	results, err := plan.Apply(client)
+	cmdutil.PrintActionResults(results)
+	return err
+}
+
+func indent(text string, prefix string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// printPlan prints the changes in the same way as a Terraform plan.
+func printPlan(plan *apply.Plan) {
+	if len(plan.Changes) == 0 {
+		fmt.Printf("No changes. %d jobs are up to date.\n", plan.Unchanged)
+		return
+	}
+
+	fmt.Println("jenkinsctl will perform the following actions:")
+	fmt.Println()
+	symbols := map[string]string{
+		apply.CHANGE_CREATE: "+",
+		apply.CHANGE_UPDATE: "~",
+		apply.CHANGE_DELETE: "-",
+	}
+	for _, change := range plan.Changes {
+		name := change.Name
+		if change.Folder {
+			name += " (folder)"
+		}
+		fmt.Printf("  %s %s\n", symbols[change.Action], name)
+		if change.Action == apply.CHANGE_UPDATE {
+			current, _ := jobs.CanonicalizeConfig(change.Current, true)
+			expected, _ := jobs.CanonicalizeConfig(change.Config, true)
+			fmt.Println(indent(diff.Unified("current", "manifest", current, expected, 2), "      "))
+		}
+	}
+	fmt.Printf(
+		"\nPlan: %d to create, %d to update, %d to delete.\n",
+		plan.Count(apply.CHANGE_CREATE), plan.Count(apply.CHANGE_UPDATE), plan.Count(apply.CHANGE_DELETE),
+	)
+}
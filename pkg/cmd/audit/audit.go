@@ -0,0 +1,50 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"jenkinsctl/pkg/apiclient"
+
+	"github.com/spf13/cobra"
+)
+
+func NewAuditCmd(client *apiclient.ApiClient) *cobra.Command {
+
+	// cmd represents the audit command
+	var cmd = &cobra.Command{
+		Use:   "audit",
+		Short: "This command allows to review the actions run on the jobs",
+		Long: `This command allows to review the audit log where the actions run on the jobs
+are recorded, with the user who ran them and their outcome for each job.
+
+The audit log is written in the file given by the audit.path key of the
+configuration file, or the AUDIT_PATH environment variable, and by default in
+$XDG_STATE_HOME/jenkinsctl/audit.jsonl (~/.local/state/jenkinsctl/audit.jsonl).
+
+For example:
+
+show the actions of the last day:
+	jenkinsctl audit show --since=1d
+	jenkinsctl audit show --action=stop --job='deploy-*' --status=failed`,
+		// The audit log is read without connecting to Jenkins, so that it can
+		// be reviewed when Jenkins is down.
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	}
+
+	cmd.AddCommand(NewAuditShowCmd(client))
+	return cmd
+}
@@ -0,0 +1,182 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/audit"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"jenkinsctl/pkg/timeutil"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type AuditShowFlags struct {
+	File       string
+	Since      string
+	Until      string
+	Action     string
+	Job        string
+	User       string
+	Controller string
+	Status     string
+	Limit      int
+	Format     string
+}
+
+func newAuditShowFlags() *AuditShowFlags {
+	return &AuditShowFlags{
+		File:       "",
+		Since:      "",
+		Until:      "",
+		Action:     "",
+		Job:        "",
+		User:       "",
+		Controller: "",
+		Status:     "",
+		Limit:      0,
+		Format:     "table",
+	}
+}
+
+func NewAuditShowCmd(client *apiclient.ApiClient) *cobra.Command {
+	auditShowFlags := newAuditShowFlags()
+
+	// cmd represents the audit show command
+	var cmd = &cobra.Command{
+		Use:   "show",
+		Short: "show the actions recorded in the audit log",
+		Long: `This command will show the actions recorded in the audit log, the oldest first,
+with the OS and Jenkins users who ran them, the Jenkins controller, the
+affected jobs and their outcome. The filters are combined, --job selects the
+actions affecting at least one job matching the glob pattern and --status the
+actions with at least one job in this status (done, skipped or failed)
+For example:
+	jenkinsctl audit show
+	jenkinsctl audit show --since=2026-10-01 --until=yesterday
+	jenkinsctl audit show --action=start --job='deploy-*' --user=alice
+	jenkinsctl audit show --status=failed --limit=20 --format=json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return auditShow(auditShowFlags)
+		},
+	}
+
+	cmd.Flags().SortFlags = false
+	cmd.Flags().StringVar(
+		&auditShowFlags.Since, "since", auditShowFlags.Since,
+		"Only the actions run since the date (like 2026-10-01, yesterday or 2d ago)",
+	)
+	cmd.Flags().StringVar(
+		&auditShowFlags.Until, "until", auditShowFlags.Until,
+		"Only the actions run until the date (like 2026-10-01, yesterday or 2d ago)",
+	)
+	cmd.Flags().StringVar(
+		&auditShowFlags.Action, "action", auditShowFlags.Action,
+		"Only the actions of this kind (like start, stop, schedule, disable, enable or delete)",
+	)
+	cmd.Flags().StringVar(
+		&auditShowFlags.Job, "job", auditShowFlags.Job,
+		"Only the actions affecting a job matching the glob pattern",
+	)
+	cmd.Flags().StringVar(
+		&auditShowFlags.User, "user", auditShowFlags.User,
+		"Only the actions run by this OS or Jenkins user, or requested by this client of the API server",
+	)
+	cmd.Flags().StringVar(
+		&auditShowFlags.Controller, "controller", auditShowFlags.Controller,
+		"Only the actions run on a Jenkins controller whose address contains the text",
+	)
+	cmd.Flags().StringVar(
+		&auditShowFlags.Status, "status", auditShowFlags.Status,
+		"Only the actions with a job in this status (possible values: done, skipped, failed)",
+	)
+	cmd.Flags().IntVar(
+		&auditShowFlags.Limit, "limit", auditShowFlags.Limit,
+		"Only the last actions, all of them when 0",
+	)
+	cmd.Flags().StringVar(
+		&auditShowFlags.Format, "format", auditShowFlags.Format,
+		"Output format (possible values: table, json)",
+	)
+	cmd.Flags().StringVar(
+		&auditShowFlags.File, "file", auditShowFlags.File,
+		"Audit log to read instead of the configured one",
+	)
+	return cmd
+}
+
+func (flags *AuditShowFlags) filter() (*audit.Filter, error) {
+	filter := &audit.Filter{
+		Action:     flags.Action,
+		Job:        flags.Job,
+		User:       flags.User,
+		Controller: flags.Controller,
+		Status:     flags.Status,
+	}
+	now := time.Now()
+	var err error
+	if flags.Since != "" {
+		if filter.Since, err = timeutil.ParseTime(flags.Since, now); err != nil {
+			return nil, err
+		}
+	}
+	if flags.Until != "" {
+		if filter.Until, err = timeutil.ParseTime(flags.Until, now); err != nil {
+			return nil, err
+		}
+	}
+	return filter, nil
+}
+
+func auditShow(flags *AuditShowFlags) error {
+	if flags.Format != "table" && flags.Format != "json" {
+		return fmt.Errorf("%s is not accepted format (possible values: table, json)", flags.Format)
+	}
+	if flags.Limit < 0 {
+		return errors.New("--limit must be positive")
+	}
+	filter, err := flags.filter()
+	if err != nil {
+		return err
+	}
+	path := flags.File
+	if path == "" {
+		if path, err = cmdutil.AuditPath(); err != nil {
+			return err
+		}
+	}
+	entries, err := audit.Read(path, filter)
+	if err != nil {
+		return err
+	}
+	if flags.Limit > 0 && len(entries) > flags.Limit {
+		entries = entries[len(entries)-flags.Limit:]
+	}
+	if flags.Format == "json" {
+		return audit.WriteEntriesJSON(os.Stdout, entries)
+	}
+	if len(entries) == 0 {
+		fmt.Println("no action matches your rules")
+		return nil
+	}
+	audit.PrintEntriesTable(os.Stdout, entries)
+	return nil
+}
@@ -0,0 +1,46 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"jenkinsctl/pkg/apiclient"
+
+	"github.com/spf13/cobra"
+)
+
+func NewBackupCmd(client *apiclient.ApiClient) *cobra.Command {
+
+	// cmd represents the backup command
+	var cmd = &cobra.Command{
+		Use:   "backup",
+		Short: "This command allows to backup and restore the jobs configuration",
+		Long: `This command allows to backup and restore the jobs configuration
+
+For example:
+
+create a backup:
+	jenkinsctl backup create --dir=./snap
+
+restore a backup:
+	jenkinsctl backup restore --dir=./snap --dry-run
+	jenkinsctl backup restore --dir=./snap --only="my-folder/*"`,
+	}
+
+	cmd.AddCommand(NewBackupCreateCmd(client))
+	cmd.AddCommand(NewBackupRestoreCmd(client))
+	return cmd
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/backup"
+	"jenkinsctl/pkg/apiclient/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+type BackupCreateFlags struct {
+	Dir string
+}
+
+func newBackupCreateFlags() *BackupCreateFlags {
+	return &BackupCreateFlags{
+		Dir: "",
+	}
+}
+
+func NewBackupCreateCmd(client *apiclient.ApiClient) *cobra.Command {
+	backupCreateFlags := newBackupCreateFlags()
+
+	// cmd represents the backup create command
+	var cmd = &cobra.Command{
+		Use:   "create",
+		Short: "backup the configuration of all jobs",
+		Long: `This command will download the config.xml of every job and folder in a directory
+For example:
+	jenkinsctl backup create --dir=./snap`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return backupCreate(client, backupCreateFlags)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&backupCreateFlags.Dir, "dir", backupCreateFlags.Dir,
+		"Directory where the backup is saved",
+	)
+	cmd.MarkFlagRequired("dir")
+	return cmd
+}
+
+func backupCreate(client *apiclient.ApiClient, flags *BackupCreateFlags) error {
+	fmt.Println("Saving jobs...")
+	results, err := backup.Create(client, flags.Dir)
+	for _, result := range results {
+		if result.Status == jobs.ACTION_STATUS_FAILED {
+			fmt.Println(result.Message)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("backup of %d jobs saved in %s\n", len(results), flags.Dir)
+	return nil
+}
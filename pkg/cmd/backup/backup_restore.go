@@ -0,0 +1,142 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/backup"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+type BackupRestoreFlags struct {
+	Dir    string
+	Only   string
+	DryRun string
+	Force  bool
+}
+
+func newBackupRestoreFlags() *BackupRestoreFlags {
+	return &BackupRestoreFlags{
+		Dir:    "",
+		Only:   "",
+		DryRun: jobs.DRY_RUN_NONE,
+		Force:  false,
+	}
+}
+
+func NewBackupRestoreCmd(client *apiclient.ApiClient) *cobra.Command {
+	backupRestoreFlags := newBackupRestoreFlags()
+
+	// cmd represents the backup restore command
+	var cmd = &cobra.Command{
+		Use:   "restore",
+		Short: "restore the configuration of jobs from a backup",
+		Long: `This command will create the missing jobs and update the changed ones from a backup
+For example:
+	jenkinsctl backup restore --dir=./snap --dry-run
+	jenkinsctl backup restore --dir=./snap --only="my-folder/*"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return backupRestore(client, backupRestoreFlags)
+		},
+	}
+
+	cmd.Flags().SortFlags = false
+	cmd.Flags().StringVar(
+		&backupRestoreFlags.Dir, "dir", backupRestoreFlags.Dir,
+		"Directory of the backup",
+	)
+	cmd.Flags().StringVar(
+		&backupRestoreFlags.Only, "only", backupRestoreFlags.Only,
+		"Only restore the jobs whose full name matches this pattern",
+	)
+	cmdutil.AddDryRunFlag(
+		cmd, &backupRestoreFlags.DryRun,
+		"Only show what would change without restoring the jobs, Jenkins having no validation for the server mode",
+	)
+	cmd.Flags().BoolVar(
+		&backupRestoreFlags.Force, "force", backupRestoreFlags.Force,
+		"Do not ask for confirmation before restoring",
+	)
+	cmd.MarkFlagRequired("dir")
+	return cmd
+}
+
+func backupRestore(client *apiclient.ApiClient, flags *BackupRestoreFlags) error {
+	manifest, err := backup.ReadManifest(flags.Dir)
+	if err != nil {
+		return err
+	}
+	plan, err := manifest.PlanRestore(client, flags.Dir, flags.Only)
+	if err != nil {
+		return err
+	}
+	if len(plan.Actions) == 0 {
+		return fmt.Errorf("no job of the backup matches %s", flags.Only)
+	}
+
+	fmt.Println("\nJobs to be restored :")
+	printRestorePlan(plan)
+	if failed := plan.Failed(); failed > 0 {
+		return fmt.Errorf("%d jobs could not be compared with the backup", failed)
+	}
+	if flags.DryRun != jobs.DRY_RUN_NONE {
+		for _, action := range plan.Actions {
+			if action.Err == nil && action.Action == backup.RESTORE_UPDATE {
+				fmt.Println(action.Diff())
+			}
+		}
+		cmdutil.WarnClientOnlyDryRun(flags.DryRun)
+		return nil
+	}
+	if !plan.HasChanges() {
+		fmt.Println("all jobs are up to date")
+		return nil
+	}
+	if !flags.Force {
+		err = cmdutil.AskUserForYesOrNo("restore")
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Println("Restoring jobs...")
+	results, err := plan.Apply(client)
+	cmdutil.PrintActionResults(results)
+	return err
+}
+
+func printRestorePlan(plan *backup.RestorePlan) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Type", "Action"})
+	for _, action := range plan.Actions {
+		itemType := "job"
+		if action.Item.IsFolder() {
+			itemType = "folder"
+		}
+		actionStr := action.Action
+		if action.Err != nil {
+			actionStr = "error: " + action.Err.Error()
+		}
+		table.Append([]string{action.Item.Name, itemType, actionStr})
+	}
+	table.Render()
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdutil
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/jenkinsctl"
+)
+
+// PrintActionHeader prints the action run on the jobs, like "Starting jobs...".
+func PrintActionHeader(action string, dryRun string) {
+	if dryRun != jobs.DRY_RUN_NONE {
+		fmt.Printf("%s jobs (dry run, %s)...\n", action, dryRun)
+		return
+	}
+	fmt.Printf("%s jobs...\n", action)
+}
+
+// PrintActionResults prints the message of each result, followed by its
+// config.xml diff on a dry run.
+func PrintActionResults(results []jobs.ActionResult) {
+	for _, result := range results {
+		fmt.Println(result.Message)
+		if result.Diff != "" {
+			fmt.Println(result.Diff)
+		}
+	}
+}
+
+// NewLibraryClient returns a client of the library for the Jenkins server of
+// the command line client, which prints the result of the actions on each job
+// as soon as it is known.
+func NewLibraryClient(client *apiclient.ApiClient, dryRun string) (*jenkinsctl.Client, error) {
+	return jenkinsctl.New(jenkinsctl.Options{
+		Address:               client.ClientConfig.Address,
+		Username:              client.ClientConfig.Username,
+		Token:                 client.ClientConfig.Token,
+		MaxConcurrentRequests: client.ClientConfig.MaxConcurentRequests,
+		DryRun:                dryRun,
+		Audit:                 client.Audit,
+		OnEvent: func(event jenkinsctl.Event) {
+			PrintActionResults([]jobs.ActionResult{event.Result})
+		},
+	})
+}
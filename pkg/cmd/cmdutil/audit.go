@@ -0,0 +1,59 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdutil
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/audit"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// AuditPath returns the path of the audit log, given by the audit.path key of
+// the configuration (or the AUDIT_PATH environment variable), or else the
+// default path under the state directory of the user.
+func AuditPath() (string, error) {
+	if path := viper.GetString("audit.path"); path != "" {
+		return path, nil
+	}
+	return audit.DefaultPath()
+}
+
+// AppendAudit adds the entry to the audit log, it is the audit hook of the
+// client. The action having already been run, a failure to write the log is
+// only reported as a warning.
+func AppendAudit(entry *audit.Entry) {
+	path, err := AuditPath()
+	if err != nil {
+		warnAudit(err)
+		return
+	}
+	AppendAuditFile(path, entry)
+}
+
+// AppendAuditFile adds the entry to the audit log written in file, like
+// AppendAudit.
+func AppendAuditFile(file string, entry *audit.Entry) {
+	warnAudit(audit.Append(file, entry))
+}
+
+func warnAudit(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: the action could not be added to the audit log:", err)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// AskUserForYesOrNo asks the user to confirm the action on the listed items
+// and returns an error unless the answer is yes.
+func AskUserForYesOrNo(action string) error {
+	return AskUserForConfirmation(fmt.Sprintf("Do you want to %s these jobs ?", action))
+}
+
+// AskUserForConfirmation asks the question and returns an error unless the
+// answer is yes.
+func AskUserForConfirmation(question string) error {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Printf("\n%s (yes or no): ", question)
+	userInput, _ := reader.ReadString('\n')
+	if userInput == "no\n" {
+		return errors.New("user canceled")
+	} else if userInput != "yes\n" {
+		return fmt.Errorf("unrecognized command: %s", userInput)
+	}
+	fmt.Println()
+	return nil
+}
+
+// IsTerminal reports whether the file is a terminal rather than a pipe or a
+// regular file.
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,134 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdutil
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// LoadClientConfig reads the configuration of the Jenkins server from the
+// jenkins section, or from the contexts.<name> section when contextName is not
+// empty. When server is not empty, it replaces the address of the
+// configuration, the credentials being only taken from a section defined for
+// this address so that they are never sent to another server.
+func LoadClientConfig(contextName string, server string) (*apiclient.ApiClientConfig, error) {
+
+	viper.SetDefault("jenkins.max_concurent", 3)
+
+	prefix := "jenkins"
+	if contextName != "" {
+		prefix = "contexts." + contextName
+		if !viper.IsSet(prefix) {
+			return nil, fmt.Errorf("context %s not defined", contextName)
+		}
+	}
+	if server != "" && !sameAddress(viper.GetString(prefix+".addr"), server) {
+		if contextName != "" {
+			return nil, fmt.Errorf(
+				"context %s is not defined for %s, its credentials are not sent to another server",
+				contextName, server,
+			)
+		}
+		prefix = serverPrefix(server)
+		if prefix == "" {
+			return nil, fmt.Errorf(
+				"no context defined for %s, add one with its credentials to use this server", server,
+			)
+		}
+	}
+
+	config := &apiclient.ApiClientConfig{
+		Address:              viper.GetString(prefix + ".addr"),
+		Username:             viper.GetString(prefix + ".user"),
+		Token:                viper.GetString(prefix + ".token"),
+		MaxConcurentRequests: viper.GetInt("jenkins.max_concurent"),
+	}
+	if server != "" {
+		config.Address = server
+	}
+	missingConfig := false
+	var errorMessage = "\n"
+	if config.Address == "" {
+		missingConfig = true
+		errorMessage = errorMessage + "jenkins server address not defined\n"
+	}
+	if config.Username == "" {
+		missingConfig = true
+		errorMessage = errorMessage + "jenkins server username not defined\n"
+	}
+	if config.Token == "" {
+		missingConfig = true
+		errorMessage = errorMessage + "jenkins server token not defined\n"
+	}
+	if missingConfig {
+		return nil, errors.New(errorMessage)
+	}
+	return config, nil
+}
+
+// sameAddress reports whether two addresses designate the same Jenkins server,
+// ignoring a trailing slash and the case of the scheme and host.
+func sameAddress(a string, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "/"), strings.TrimSuffix(b, "/"))
+}
+
+// serverPrefix returns the configuration section defined for server, the first
+// context in name order when several are, or an empty string when none is.
+func serverPrefix(server string) string {
+	if sameAddress(viper.GetString("jenkins.addr"), server) {
+		return "jenkins"
+	}
+	names := make([]string, 0)
+	for name := range viper.GetStringMap("contexts") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if sameAddress(viper.GetString("contexts."+name+".addr"), server) {
+			return "contexts." + name
+		}
+	}
+	return ""
+}
+
+// ConnectClient connects the client of the command line to the Jenkins server
+// of the jenkins section of the configuration.
+func ConnectClient(client *apiclient.ApiClient) error {
+	config, err := LoadClientConfig("", "")
+	if err != nil {
+		return err
+	}
+	return client.Connect(config)
+}
+
+// NewContextClient returns a client connected to the Jenkins server of a
+// context of the configuration file, or of the jenkins section when
+// contextName is empty. When server is not empty, the client connects to this
+// address with the credentials of the context defined for it.
+func NewContextClient(contextName string, server string) (*apiclient.ApiClient, error) {
+	config, err := LoadClientConfig(contextName, server)
+	if err != nil {
+		return nil, err
+	}
+	return apiclient.NewConnectedClient(config)
+}
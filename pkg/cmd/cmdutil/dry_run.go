@@ -0,0 +1,89 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdutil
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// dryRunValue is the value of the --dry-run flag, client when the flag is
+// given without a value. true and false are accepted for the scripts written
+// when the flag was a boolean.
+type dryRunValue string
+
+func (value *dryRunValue) String() string {
+	return string(*value)
+}
+
+func (value *dryRunValue) Set(text string) error {
+	switch text {
+	case "true":
+		text = jobs.DRY_RUN_CLIENT
+	case "false":
+		text = jobs.DRY_RUN_NONE
+	}
+	if err := jobs.CheckDryRun(text); err != nil {
+		return err
+	}
+	*value = dryRunValue(text)
+	return nil
+}
+
+func (value *dryRunValue) Type() string {
+	return "mode"
+}
+
+// AddDryRunFlag adds the --dry-run flag, whose value is one of the
+// jobs.DRY_RUN_* modes, to a command changing the jobs.
+func AddDryRunFlag(cmd *cobra.Command, dryRun *string, usage string) {
+	if *dryRun == "" {
+		*dryRun = jobs.DRY_RUN_NONE
+	}
+	flag := cmd.Flags().VarPF((*dryRunValue)(dryRun), "dry-run", "", usage)
+	flag.NoOptDefVal = jobs.DRY_RUN_CLIENT
+}
+
+// WarnClientOnlyDryRun tells the user that the controller can not validate
+// the action of the command, for which the server dry run is then the same as
+// the client one.
+func WarnClientOnlyDryRun(dryRun string) {
+	if dryRun == jobs.DRY_RUN_SERVER {
+		fmt.Fprintln(os.Stderr, "Warning: Jenkins can not validate this action, only the client dry run is done")
+	}
+}
+
+// CheckDryRunResults returns the error of the dry run, or an error when it
+// found actions which would fail, so that scripts can rely on the exit status.
+func CheckDryRunResults(results []jobs.ActionResult, err error) error {
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Status == jobs.ACTION_STATUS_FAILED {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d jobs failed the dry run", failed)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmdutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/jenkinsctl"
+	"jenkinsctl/pkg/selector"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// JobSelectionFlags are the flags shared by the commands selecting jobs from
+// their name.
+type JobSelectionFlags struct {
+	Names    []string
+	Regex    string
+	Exclude  []string
+	FromFile string
+	Selector string
+}
+
+func AddJobSelectionFlags(cmd *cobra.Command, flags *JobSelectionFlags) {
+	cmd.Flags().StringArrayVar(
+		&flags.Names, "name", flags.Names,
+		"Filter Jobs from the name, accepts glob patterns like 'team/*' and can be repeated",
+	)
+	cmd.Flags().StringVar(
+		&flags.Regex, "regex", flags.Regex,
+		"Filter Jobs whose full name matches the regular expression",
+	)
+	cmd.Flags().StringArrayVar(
+		&flags.Exclude, "exclude", flags.Exclude,
+		"Exclude Jobs matching the glob pattern, can be repeated",
+	)
+	cmd.Flags().StringVar(
+		&flags.FromFile, "from-file", flags.FromFile,
+		"Read the names of the jobs from a file, one per line (- for stdin)",
+	)
+	cmd.Flags().StringVar(
+		&flags.Selector, "selector", flags.Selector,
+		"Filter Jobs with an expression, like 'status in (failure, unstable) && age < 2h'",
+	)
+}
+
+// FromStdin reports whether the names are read from stdin, in which case the
+// user can not be asked for a confirmation.
+func (flags *JobSelectionFlags) FromStdin() bool {
+	return flags.FromFile == "-"
+}
+
+// CheckConfirmation returns an error when the confirmation would be asked
+// while stdin is used to read the names.
+func (flags *JobSelectionFlags) CheckConfirmation(force bool) error {
+	if flags.FromStdin() && !force {
+		return errors.New("--force is required when the names are read from stdin")
+	}
+	return nil
+}
+
+func (flags *JobSelectionFlags) IsEmpty() bool {
+	return len(flags.Names) == 0 && flags.Regex == "" &&
+		len(flags.Exclude) == 0 && flags.FromFile == "" &&
+		flags.Selector == ""
+}
+
+func readNames(reader io.Reader) ([]string, error) {
+	names := []string{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, scanner.Err()
+}
+
+// names returns the names given with --name followed by the ones read from
+// --from-file, without duplicates.
+func (flags *JobSelectionFlags) names() ([]string, error) {
+	names := append([]string{}, flags.Names...)
+	if flags.FromFile != "" {
+		reader := os.Stdin
+		if !flags.FromStdin() {
+			file, err := os.Open(flags.FromFile)
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+			reader = file
+		}
+		fileNames, err := readNames(reader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %s", flags.FromFile, err)
+		}
+		if len(fileNames) == 0 {
+			return nil, fmt.Errorf("no job name found in %s", flags.FromFile)
+		}
+		names = append(names, fileNames...)
+	}
+
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return unique, nil
+}
+
+// SetFilter sets the name and selector filters, the names read from
+// --from-file are added to the ones given with --name.
+func (flags *JobSelectionFlags) SetFilter(filter *jobs.JobsFilterParams) error {
+	names, err := flags.names()
+	if err != nil {
+		return err
+	}
+	filter.Names = append(filter.Names, names...)
+	filter.Regex = flags.Regex
+	filter.Exclude = flags.Exclude
+
+	if flags.Selector != "" {
+		jobSelector, err := selector.Compile(flags.Selector, jobs.SelectorSchema)
+		if err != nil {
+			return fmt.Errorf("invalid selector: %s", err)
+		}
+		filter.Selector = jobSelector
+	}
+	return nil
+}
+
+// SetLibraryFilter sets the name and selector filters of a filter of the
+// library like SetFilter.
+func (flags *JobSelectionFlags) SetLibraryFilter(filter *jenkinsctl.Filter) error {
+	names, err := flags.names()
+	if err != nil {
+		return err
+	}
+	filter.Names = append(filter.Names, names...)
+	filter.Regex = flags.Regex
+	filter.Exclude = flags.Exclude
+	filter.Selector = flags.Selector
+	return nil
+}
+
+// ExactNames returns glob patterns matching exactly the names of the jobs, to
+// run an action on the jobs confirmed by the user.
+func ExactNames(summaries []jobs.JobSummary) []string {
+	escaper := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`)
+	names := make([]string, len(summaries))
+	for i := range summaries {
+		names[i] = escaper.Replace(summaries[i].Name)
+	}
+	return names
+}
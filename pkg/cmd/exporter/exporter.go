@@ -0,0 +1,113 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/exporter"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type ExporterFlags struct {
+	cmdutil.JobSelectionFlags
+	Listen      string
+	MetricsPath string
+	CacheTTL    time.Duration
+}
+
+func newExporterFlags() *ExporterFlags {
+	return &ExporterFlags{
+		Listen:      ":9118",
+		MetricsPath: "/metrics",
+		CacheTTL:    30 * time.Second,
+	}
+}
+
+func NewExporterCmd(client *apiclient.ApiClient) *cobra.Command {
+	exporterFlags := newExporterFlags()
+
+	// cmd represents the exporter command
+	var cmd = &cobra.Command{
+		Use:   "exporter",
+		Short: "expose the metrics of the jobs to Prometheus",
+		Long: `This command runs a Prometheus exporter: on each scrape, it lists the jobs and exposes
+the result, duration and age of their last build, the number of running jobs, the length
+of the build queue and the state and executor usage of the nodes
+
+The metrics are collected at most once per cache TTL, the scrapes in between getting the
+cached metrics, and the jobs are listed with the concurrency of the configuration
+For example:
+	jenkinsctl exporter
+	jenkinsctl exporter --listen=:9118 --cache-ttl=1m
+	jenkinsctl exporter --name='team/*' --exclude='team/sandbox-*'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExporter(client, exporterFlags)
+		},
+	}
+
+	cmd.Flags().SortFlags = false
+	cmdutil.AddJobSelectionFlags(cmd, &exporterFlags.JobSelectionFlags)
+	cmd.Flags().StringVar(
+		&exporterFlags.Listen, "listen", exporterFlags.Listen,
+		"Address on which the metrics are served",
+	)
+	cmd.Flags().StringVar(
+		&exporterFlags.MetricsPath, "metrics-path", exporterFlags.MetricsPath,
+		"Path under which the metrics are served",
+	)
+	cmd.Flags().DurationVar(
+		&exporterFlags.CacheTTL, "cache-ttl", exporterFlags.CacheTTL,
+		"Duration during which the collected metrics are served without querying Jenkins again",
+	)
+	return cmd
+}
+
+func runExporter(client *apiclient.ApiClient, flags *ExporterFlags) error {
+	if flags.CacheTTL < 0 {
+		return errors.New("--cache-ttl must be positive")
+	}
+	if flags.FromStdin() {
+		return errors.New("the names of the jobs can not be read from stdin")
+	}
+	filter := jobs.JobsFilterParams{
+		Status:    jobs.JOB_STATUS_ALL,
+		Recursive: true,
+	}
+	if err := flags.SetFilter(&filter); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(flags.MetricsPath, exporter.NewExporter(client, &filter, flags.CacheTTL))
+	mux.HandleFunc("/", func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/" {
+			http.NotFound(writer, request)
+			return
+		}
+		fmt.Fprintf(writer, "jenkinsctl exporter, the metrics are served under %s\n", flags.MetricsPath)
+	})
+	fmt.Fprintf(os.Stderr, "Serving the metrics on %s%s\n", flags.Listen, flags.MetricsPath)
+	return http.ListenAndServe(flags.Listen, mux)
+}
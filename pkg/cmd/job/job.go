@@ -17,12 +17,10 @@ limitations under the License.
 package job
 
 import (
-	"bufio"
-	"errors"
 	"fmt"
 	"jenkinsctl/pkg/apiclient"
 	"jenkinsctl/pkg/apiclient/jobs"
-	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -49,35 +47,72 @@ start jobs:
 
 stop jobs:
 	jenkinsctl job stop --minimum-age=1h
-	jenkinsctl job stop --name=my-app`,
+	jenkinsctl job stop --name=my-app
+
+show the stages of a pipeline build:
+	jenkinsctl job stages my-app --failed-only
+
+approve or abort the input steps of pipelines:
+	jenkinsctl job input list
+	jenkinsctl job input approve deploy-prod --param=VERSION=1.2.3
+
+download the artifacts of a build:
+	jenkinsctl job artifacts get my-app --build=lastSuccessful --glob='*.jar'
+
+show the test results and the flaky tests:
+	jenkinsctl job tests my-app
+	jenkinsctl job tests flaky my-app --builds=20
+
+compare two builds of a job:
+	jenkinsctl job compare my-app lastSuccessful lastFailed
+
+show the commits of the builds and the culprits of a failure:
+	jenkinsctl job changes my-app --since-build=40
+	jenkinsctl job changes my-app --culprits
+
+disable and enable jobs:
+	jenkinsctl job disable --name=my-app --reason="incident #42"
+	jenkinsctl job enable --name=my-app
+
+create, copy, rename and delete jobs:
+	jenkinsctl job create my-folder/my-app -f config.xml
+	jenkinsctl job copy my-app my-app-copy
+	jenkinsctl job rename my-app-copy my-other-app
+	jenkinsctl job delete --name=my-other-app
+
+edit jobs configuration:
+	jenkinsctl job config set --xpath=//logRotator/numToKeep --value=10 --dry-run
+	jenkinsctl job config delete --xpath=//logRotator --name=my-app`,
 	}
 
 	cmd.AddCommand(NewJobListCmd(client))
 	cmd.AddCommand(NewJobStartCmd(client))
 	cmd.AddCommand(NewJobStopCmd(client))
+	cmd.AddCommand(NewJobStagesCmd(client))
+	cmd.AddCommand(NewJobInputCmd(client))
+	cmd.AddCommand(NewJobArtifactsCmd(client))
+	cmd.AddCommand(NewJobTestsCmd(client))
+	cmd.AddCommand(NewJobCompareCmd(client))
+	cmd.AddCommand(NewJobChangesCmd(client))
+	cmd.AddCommand(NewJobDisableCmd(client))
+	cmd.AddCommand(NewJobEnableCmd(client))
+	cmd.AddCommand(NewJobCreateCmd(client))
+	cmd.AddCommand(NewJobCopyCmd(client))
+	cmd.AddCommand(NewJobRenameCmd(client))
+	cmd.AddCommand(NewJobDeleteCmd(client))
+	cmd.AddCommand(NewJobConfigCmd(client))
 	return cmd
 }
 
-func checkStatusValidValue(status string) error {
-	if status != jobs.JOB_STATUS_ALL &&
-		status != jobs.JOB_STATUS_RUNNING &&
-		status != jobs.JOB_STATUS_SUCCESS &&
-		status != jobs.JOB_STATUS_FAILED &&
-		status != jobs.JOB_STATUS_ABORTED {
-		return fmt.Errorf("%s is not accepted status", status)
-	}
-	return nil
-}
+// statusFlagUsage is the help of the --status flags, built from the statuses
+// known by the jobs package.
+var statusFlagUsage = fmt.Sprintf(
+	"Filter Jobs from status, separated by commas and prefixed with ! to exclude them, "+
+		"a disabled job matching both disabled and the status of its last build (possible values: %s)",
+	strings.Join(append([]string{jobs.JOB_STATUS_ALL}, jobs.JobStatuses...), ", "),
+)
 
-func askUserForYesOrNo(action string) error {
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Printf("\nDo you want to %s these jobs ? (yes or no): ", action)
-	userInput, _ := reader.ReadString('\n')
-	if userInput == "no\n" {
-		return errors.New("user canceled")
-	} else if userInput != "yes\n" {
-		return fmt.Errorf("unrecognized command: %s", userInput)
-	}
-	fmt.Println()
-	return nil
+func checkStatusValidValue(status string) error {
+	_, err := jobs.ParseStatusFilter(status)
+	return err
 }
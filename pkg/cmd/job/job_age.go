@@ -0,0 +1,123 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package job
+
+import (
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/jenkinsctl"
+	"jenkinsctl/pkg/timeutil"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// durationFlag accepts a duration like 90s, 1h30m, 2d or 1w, a plain number
+// being a number of minutes as with the first versions of the age flags.
+type durationFlag time.Duration
+
+func (flag *durationFlag) String() string {
+	if *flag == 0 {
+		return ""
+	}
+	return time.Duration(*flag).String()
+}
+
+func (flag *durationFlag) Set(value string) error {
+	if _, err := strconv.Atoi(value); err == nil {
+		value += "m"
+	}
+	duration, err := timeutil.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*flag = durationFlag(duration)
+	return nil
+}
+
+func (flag *durationFlag) Type() string {
+	return "duration"
+}
+
+// timeFlag accepts a date like 2026-10-01T08:00, today, yesterday or 2h ago.
+type timeFlag time.Time
+
+func (flag *timeFlag) String() string {
+	if time.Time(*flag).IsZero() {
+		return ""
+	}
+	return time.Time(*flag).Format("2006-01-02T15:04:05")
+}
+
+func (flag *timeFlag) Set(value string) error {
+	date, err := timeutil.ParseTime(value, time.Now())
+	if err != nil {
+		return err
+	}
+	*flag = timeFlag(date)
+	return nil
+}
+
+func (flag *timeFlag) Type() string {
+	return "time"
+}
+
+// JobAgeFlags are the flags shared by the commands selecting jobs from the
+// start date of their last build.
+type JobAgeFlags struct {
+	AgeMin durationFlag
+	AgeMax durationFlag
+	Since  timeFlag
+	Until  timeFlag
+}
+
+func addJobAgeFlags(cmd *cobra.Command, flags *JobAgeFlags) {
+	cmd.Flags().Var(
+		&flags.AgeMin, "minimum-age",
+		"Filter Jobs from last build minimum age (like 90s, 45m, 1h30m, 2d, 1w or a number of minutes)",
+	)
+	cmd.Flags().Var(
+		&flags.AgeMax, "maximum-age",
+		"Filter Jobs from last build maximum age (like 90s, 45m, 1h30m, 2d, 1w or a number of minutes)",
+	)
+	cmd.Flags().Var(
+		&flags.Since, "since",
+		"Filter Jobs whose last build started after the date (like 2026-10-01T08:00, today, yesterday or 2h ago)",
+	)
+	cmd.Flags().Var(
+		&flags.Until, "until",
+		"Filter Jobs whose last build started before the date (like 2026-10-01T08:00, today, yesterday or 2h ago)",
+	)
+}
+
+func (flags *JobAgeFlags) hasAgeFilter() bool {
+	return flags.AgeMin != 0 || flags.AgeMax != 0 ||
+		!time.Time(flags.Since).IsZero() || !time.Time(flags.Until).IsZero()
+}
+
+func (flags *JobAgeFlags) setAgeFilter(filter *jobs.JobsFilterParams) {
+	filter.AgeMin = time.Duration(flags.AgeMin)
+	filter.AgeMax = time.Duration(flags.AgeMax)
+	filter.Since = time.Time(flags.Since)
+	filter.Until = time.Time(flags.Until)
+}
+
+func (flags *JobAgeFlags) setLibraryAgeFilter(filter *jenkinsctl.Filter) {
+	filter.MinAge = time.Duration(flags.AgeMin)
+	filter.MaxAge = time.Duration(flags.AgeMax)
+	filter.Since = time.Time(flags.Since)
+	filter.Until = time.Time(flags.Until)
+}
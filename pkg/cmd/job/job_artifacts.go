@@ -0,0 +1,74 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package job
+
+import (
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type JobArtifactsFlags struct {
+	Build    string
+	Patterns []string
+}
+
+func NewJobArtifactsCmd(client *apiclient.ApiClient) *cobra.Command {
+
+	// cmd represents the job artifacts command
+	var cmd = &cobra.Command{
+		Use:   "artifacts",
+		Short: "list and download the artifacts of a build",
+		Long: `This command allows to list and download the files archived by a build
+
+For example:
+	jenkinsctl job artifacts list my-app --build=lastSuccessful
+	jenkinsctl job artifacts get my-app --build=42 --glob='*.jar' --output=dist`,
+	}
+
+	cmd.AddCommand(NewJobArtifactsListCmd(client))
+	cmd.AddCommand(NewJobArtifactsGetCmd(client))
+	return cmd
+}
+
+func addJobArtifactsFlags(cmd *cobra.Command, flags *JobArtifactsFlags) {
+	cmd.Flags().StringVar(
+		&flags.Build, "build", flags.Build,
+		"Number of the build, or one of "+strings.Join(jobs.BuildRefs, ", "),
+	)
+	cmd.Flags().StringArrayVar(
+		&flags.Patterns, "glob", flags.Patterns,
+		"Only the artifacts whose path or file name matches the glob pattern, the flag can be repeated",
+	)
+}
+
+// getArtifacts returns the artifacts of the build designated by the flags
+// which match the patterns.
+func getArtifacts(
+	client *apiclient.ApiClient, name string, flags *JobArtifactsFlags,
+) (*jobs.BuildArtifacts, []jobs.BuildArtifact, error) {
+	artifacts, err := jobs.GetBuildArtifacts(client, name, flags.Build)
+	if err != nil {
+		return nil, nil, err
+	}
+	selected, err := artifacts.Filter(flags.Patterns)
+	if err != nil {
+		return nil, nil, err
+	}
+	return artifacts, selected, nil
+}
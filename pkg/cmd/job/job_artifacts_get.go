@@ -0,0 +1,67 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package job
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+func NewJobArtifactsGetCmd(client *apiclient.ApiClient) *cobra.Command {
+	flags := &JobArtifactsFlags{Build: "last"}
+	output := "."
+	overwrite := false
+
+	// cmd represents the job artifacts get command
+	var cmd = &cobra.Command{
+		Use:   "get <name>",
+		Short: "download the artifacts of a build",
+		Long: `This command will download the artifacts of a build, keeping their path under the
+output directory. The downloads run in parallel, an interrupted download is
+resumed by the next call and the checksum of the files is verified when the
+build recorded their fingerprint
+For example:
+	jenkinsctl job artifacts get my-app
+	jenkinsctl job artifacts get my-app --build=lastSuccessful --glob='*.jar' --output=dist`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			artifacts, selected, err := getArtifacts(client, args[0], flags)
+			if err != nil {
+				return err
+			}
+			if len(selected) == 0 {
+				fmt.Printf("%s #%d: no artifact\n", artifacts.Job, artifacts.Number)
+				return nil
+			}
+			downloads := artifacts.Download(client, selected, output, overwrite)
+			return jobs.PrintDownloadsTable(downloads)
+		},
+	}
+
+	addJobArtifactsFlags(cmd, flags)
+	cmd.Flags().StringVarP(
+		&output, "output", "o", output,
+		"Directory where the artifacts are written",
+	)
+	cmd.Flags().BoolVar(
+		&overwrite, "overwrite", overwrite,
+		"Download again the artifacts already present in the output directory",
+	)
+	return cmd
+}
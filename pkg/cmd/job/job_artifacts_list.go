@@ -0,0 +1,55 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package job
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+func NewJobArtifactsListCmd(client *apiclient.ApiClient) *cobra.Command {
+	flags := &JobArtifactsFlags{Build: "last"}
+
+	// cmd represents the job artifacts list command
+	var cmd = &cobra.Command{
+		Use:   "list <name>",
+		Short: "list the artifacts of a build",
+		Long: `This command will list the artifacts of a build, with their MD5 checksum when
+the build recorded their fingerprint
+For example:
+	jenkinsctl job artifacts list my-app
+	jenkinsctl job artifacts list my-app --build=lastSuccessful --glob='*.jar'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			artifacts, selected, err := getArtifacts(client, args[0], flags)
+			if err != nil {
+				return err
+			}
+			if len(selected) == 0 {
+				fmt.Printf("%s #%d: no artifact\n", artifacts.Job, artifacts.Number)
+				return nil
+			}
+			jobs.PrintArtifactsTable(selected)
+			return nil
+		},
+	}
+
+	addJobArtifactsFlags(cmd, flags)
+	return cmd
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"errors"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func NewJobChangesCmd(client *apiclient.ApiClient) *cobra.Command {
+	build := "last"
+	var sinceBuild int64
+	culprits := false
+
+	// cmd represents the job changes command
+	var cmd = &cobra.Command{
+		Use:   "changes <name>",
+		Short: "show the commits built by the builds of a job",
+		Long: `This command will show the commits of the changesets of a build, or of all the builds
+after a given build, with their author, message and modified files
+With --culprits, it shows the authors of the commits built since the last successful build
+For example:
+	jenkinsctl job changes my-app
+	jenkinsctl job changes my-app --build=42
+	jenkinsctl job changes my-app --since-build=40
+	jenkinsctl job changes my-app --culprits`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if sinceBuild < 0 {
+				return errors.New("--since-build must be positive")
+			}
+			if sinceBuild > 0 && (cmd.Flags().Changed("build") || culprits) {
+				return errors.New("--since-build cannot be used with --build or --culprits")
+			}
+			if culprits {
+				authors, err := jobs.GetCulprits(client, args[0], build)
+				if err != nil {
+					return err
+				}
+				authors.PrintCulpritsTable()
+				return nil
+			}
+			builds, err := jobs.GetChanges(client, args[0], build, sinceBuild)
+			if err != nil {
+				return err
+			}
+			jobs.PrintChangesTable(builds)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&build, "build", build,
+		"Number of the build, or one of "+strings.Join(jobs.BuildRefs, ", "),
+	)
+	cmd.Flags().Int64Var(&sinceBuild, "since-build", sinceBuild, "Show the changes of the builds after this build number")
+	cmd.Flags().BoolVar(
+		&culprits, "culprits", culprits, "Show the authors of the commits built since the last successful build",
+	)
+	return cmd
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func NewJobCompareCmd(client *apiclient.ApiClient) *cobra.Command {
+	noLog := false
+	context := 3
+	logLines := 2000
+
+	// cmd represents the job compare command
+	var cmd = &cobra.Command{
+		Use:   "compare <name> <buildA> <buildB>",
+		Short: "compare two builds of a job",
+		Long: `This command will show the differences between two builds of a job: result, duration,
+agent node, causes, parameters, SCM revisions, changesets, newly failing tests and a diff
+of the console logs once their timestamps are removed
+The builds are given by their number, or by one of ` + strings.Join(jobs.BuildRefs, ", ") + `
+For example:
+	jenkinsctl job compare my-app 41 42
+	jenkinsctl job compare my-app lastSuccessful lastFailed
+	jenkinsctl job compare my-app 41 42 --no-log`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if context < 0 {
+				return errors.New("--unified must be positive")
+			}
+			if logLines < 0 {
+				return errors.New("--log-lines must be positive")
+			}
+			if noLog {
+				logLines = -1
+			}
+			comparison, err := jobs.CompareBuilds(client, args[0], args[1], args[2], logLines, context)
+			if err != nil {
+				return err
+			}
+			printComparison(comparison, !noLog)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&noLog, "no-log", noLog, "Do not compare the console logs")
+	cmd.Flags().IntVarP(&context, "unified", "U", context, "Number of context lines of the console log diff")
+	cmd.Flags().IntVar(
+		&logLines, "log-lines", logLines, "Number of last lines of the console logs to compare, 0 for the whole logs",
+	)
+	return cmd
+}
+
+func printChanges(title string, changes []jobs.BuildChange) {
+	fmt.Printf("\n%s:\n", title)
+	if len(changes) == 0 {
+		fmt.Println("  none")
+	}
+	for _, change := range changes {
+		fmt.Printf("  %s %s (%s)\n", change.ShortCommitID(), change.Summary(), change.Author.FullName)
+	}
+}
+
+func printComparison(comparison *jobs.BuildComparison, withLog bool) {
+	a, b := comparison.A, comparison.B
+	fmt.Printf("%s: #%d compared with #%d\n", a.Job, a.Number, b.Number)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"", "#" + strconv.FormatInt(a.Number, 10), "#" + strconv.FormatInt(b.Number, 10)})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	for _, field := range comparison.Fields {
+		table.Append(field[:])
+	}
+	table.Render()
+
+	printChanges(fmt.Sprintf("Changes of #%d", a.Number), comparison.ChangesA)
+	printChanges(fmt.Sprintf("Changes of #%d", b.Number), comparison.ChangesB)
+
+	fmt.Println("\nTests:")
+	switch {
+	case !comparison.TestsCompared:
+		fmt.Println("  no test report to compare")
+	case len(comparison.NewlyFailing) == 0 && len(comparison.Fixed) == 0:
+		fmt.Println("  same failed tests")
+	}
+	for _, name := range comparison.NewlyFailing {
+		fmt.Printf("  newly failing: %s\n", name)
+	}
+	for _, name := range comparison.Fixed {
+		fmt.Printf("  fixed: %s\n", name)
+	}
+
+	if !withLog {
+		return
+	}
+	fmt.Println("\nConsole log:")
+	if comparison.LogDiff == "" {
+		fmt.Println("  identical once the timestamps are removed")
+		return
+	}
+	fmt.Print(comparison.LogDiff)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"jenkinsctl/pkg/diff"
+
+	"github.com/spf13/cobra"
+)
+
+type JobConfigEditFlags struct {
+	cmdutil.JobSelectionFlags
+	JobAgeFlags
+	Status string
+	XPath  string
+	Value  string
+	DryRun string
+	Force  bool
+}
+
+func newJobConfigEditFlags() *JobConfigEditFlags {
+	return &JobConfigEditFlags{
+		Status: "all",
+		XPath:  "",
+		Value:  "",
+		DryRun: jobs.DRY_RUN_NONE,
+		Force:  false,
+	}
+}
+
+func NewJobConfigCmd(client *apiclient.ApiClient) *cobra.Command {
+
+	// cmd represents the job config command
+	var cmd = &cobra.Command{
+		Use:   "config",
+		Short: "manage the configuration of jobs",
+		Long: `This command allows to edit and compare the config.xml of jobs on Jenkins
+
+For example:
+	jenkinsctl job config set --xpath=/flow-definition/description --value="my app" --name=my-app
+	jenkinsctl job config set --xpath=//logRotator/numToKeep --value=10 --dry-run
+	jenkinsctl job config delete --xpath=/flow-definition/disabled --status=failure
+	jenkinsctl job config diff my-app my-app --context-a=prod --context-b=staging`,
+	}
+
+	cmd.AddCommand(NewJobConfigSetCmd(client))
+	cmd.AddCommand(NewJobConfigDeleteCmd(client))
+	cmd.AddCommand(NewJobConfigDiffCmd(client))
+	return cmd
+}
+
+func addJobConfigEditFlags(cmd *cobra.Command, flags *JobConfigEditFlags) {
+	cmd.Flags().SortFlags = false
+	cmdutil.AddJobSelectionFlags(cmd, &flags.JobSelectionFlags)
+	cmd.Flags().StringVar(
+		&flags.Status, "status", flags.Status,
+		statusFlagUsage,
+	)
+	addJobAgeFlags(cmd, &flags.JobAgeFlags)
+	cmd.Flags().StringVar(
+		&flags.XPath, "xpath", flags.XPath,
+		"Path of the element to edit, ending with /@name to edit an attribute",
+	)
+	cmdutil.AddDryRunFlag(
+		cmd, &flags.DryRun,
+		"Only show the differences without updating the jobs, Jenkins having no validation for the server mode",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Force, "force", flags.Force,
+		"Do not ask for confirmation before updating",
+	)
+	cmd.MarkFlagRequired("xpath")
+}
+
+func jobConfigEdit(
+	client *apiclient.ApiClient, flags *JobConfigEditFlags, edit *jobs.ConfigEdit,
+) error {
+	filter := jobs.JobsFilterParams{
+		Status: flags.Status,
+	}
+	dryRun := flags.DryRun != jobs.DRY_RUN_NONE
+	if err := flags.CheckConfirmation(flags.Force || dryRun); err != nil {
+		return err
+	}
+	if err := flags.SetFilter(&filter); err != nil {
+		return err
+	}
+	flags.setAgeFilter(&filter)
+	err := checkStatusValidValue(flags.Status)
+	if err != nil {
+		return err
+	}
+	var jobs jobs.Jobs
+	err = jobs.GetFilteredJobs(client, &filter)
+	if err != nil {
+		return err
+	}
+	if len(jobs.Jobs) == 0 {
+		return errors.New("no job matches your rules")
+	}
+
+	changes := jobs.PrepareConfigEdit(client, edit)
+	if dryRun {
+		printConfigDiffs(changes)
+		cmdutil.WarnClientOnlyDryRun(flags.DryRun)
+		return printConfigSummary(changes)
+	}
+
+	changedJobs := changes.ChangedJobs()
+	if len(changedJobs.Jobs) == 0 {
+		return printConfigSummary(changes)
+	}
+	fmt.Println("\nJobs to be updated :")
+	changedJobs.PrintJobsTable()
+	if !flags.Force {
+		err = cmdutil.AskUserForYesOrNo("update")
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Println("Updating jobs configuration...")
+	changes.Apply(client)
+	for _, change := range changes.Changes {
+		if change.IsChanged() {
+			fmt.Printf("job %s configuration is now updated\n", change.Job.Name)
+		}
+	}
+	return printConfigSummary(changes)
+}
+
+func printConfigDiffs(changes *jobs.JobConfigChanges) {
+	for _, change := range changes.Changes {
+		switch {
+		case change.Err != nil:
+			fmt.Printf("job %s: %s\n\n", change.Job.Name, change.Err)
+		case !change.IsChanged():
+			fmt.Printf("job %s: no change\n\n", change.Job.Name)
+		default:
+			fmt.Println(diff.Unified(
+				change.Job.Name+"/config.xml (current)",
+				change.Job.Name+"/config.xml (new)",
+				change.Before, change.After, 3,
+			))
+		}
+	}
+}
+
+// printConfigSummary prints the count of the jobs by outcome, and returns an
+// error when the edit failed on some jobs.
+func printConfigSummary(changes *jobs.JobConfigChanges) error {
+	changed, unchanged, failed := 0, 0, 0
+	for _, change := range changes.Changes {
+		switch {
+		case change.Err != nil:
+			failed++
+			fmt.Printf("job %s failed: %s\n", change.Job.Name, change.Err)
+		case change.IsChanged():
+			changed++
+		default:
+			unchanged++
+		}
+	}
+	fmt.Printf("\n%d changed, %d unchanged, %d failed\n", changed, unchanged, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d jobs failed", failed, len(changes.Changes))
+	}
+	return nil
+}
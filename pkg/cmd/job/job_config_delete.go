@@ -0,0 +1,48 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+func NewJobConfigDeleteCmd(client *apiclient.ApiClient) *cobra.Command {
+	jobConfigDeleteFlags := newJobConfigEditFlags()
+
+	// cmd represents the job config delete command
+	var cmd = &cobra.Command{
+		Use:   "delete",
+		Short: "delete an element of the jobs configuration",
+		Long: `This command will remove an element (or an attribute) from the config.xml of jobs
+For example:
+	jenkinsctl job config delete --xpath=//logRotator
+	jenkinsctl job config delete --xpath=//scm/@plugin --name=my-app --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			edit := jobs.ConfigEdit{
+				XPath:  jobConfigDeleteFlags.XPath,
+				Delete: true,
+			}
+			return jobConfigEdit(client, jobConfigDeleteFlags, &edit)
+		},
+	}
+
+	addJobConfigEditFlags(cmd, jobConfigDeleteFlags)
+	return cmd
+}
@@ -0,0 +1,192 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+
+	"github.com/spf13/cobra"
+)
+
+type JobConfigDiffFlags struct {
+	Context              string
+	Server               string
+	ContextA             string
+	ServerA              string
+	ContextB             string
+	ServerB              string
+	File                 string
+	IgnorePluginVersions bool
+	Unified              int
+}
+
+func newJobConfigDiffFlags() *JobConfigDiffFlags {
+	return &JobConfigDiffFlags{
+		Context:              "",
+		Server:               "",
+		ContextA:             "",
+		ServerA:              "",
+		ContextB:             "",
+		ServerB:              "",
+		File:                 "",
+		IgnorePluginVersions: false,
+		Unified:              3,
+	}
+}
+
+func NewJobConfigDiffCmd(client *apiclient.ApiClient) *cobra.Command {
+	jobConfigDiffFlags := newJobConfigDiffFlags()
+
+	// cmd represents the job config diff command
+	var cmd = &cobra.Command{
+		Use:   "diff <jobA> [<jobB>]",
+		Short: "show the differences between the configuration of two jobs",
+		Long: `This command will compare the config.xml of two jobs, of the same job on two Jenkins servers
+or of a job and a local file. Attribute order and indentation are ignored.
+For example:
+	jenkinsctl job config diff my-app my-app-copy
+	jenkinsctl job config diff my-app my-app --context-a=prod --context-b=staging
+	jenkinsctl job config diff my-app --file=config.xml --ignore-plugin-versions`,
+		Args: cobra.RangeArgs(1, 2),
+		// The default server is only connected when a job is read from it,
+		// so that two other servers can be compared when it is down.
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobConfigDiff(client, jobConfigDiffFlags, args)
+		},
+	}
+
+	cmd.Flags().SortFlags = false
+	cmd.Flags().StringVar(
+		&jobConfigDiffFlags.Context, "context", jobConfigDiffFlags.Context,
+		"Context of the configuration file used for both jobs",
+	)
+	cmd.Flags().StringVar(
+		&jobConfigDiffFlags.Server, "server", jobConfigDiffFlags.Server,
+		"Address of the Jenkins server used for both jobs, with the credentials of its context",
+	)
+	cmd.Flags().StringVar(
+		&jobConfigDiffFlags.ContextA, "context-a", jobConfigDiffFlags.ContextA,
+		"Context of the configuration file used for the first job",
+	)
+	cmd.Flags().StringVar(
+		&jobConfigDiffFlags.ServerA, "server-a", jobConfigDiffFlags.ServerA,
+		"Address of the Jenkins server used for the first job, with the credentials of its context",
+	)
+	cmd.Flags().StringVar(
+		&jobConfigDiffFlags.ContextB, "context-b", jobConfigDiffFlags.ContextB,
+		"Context of the configuration file used for the second job",
+	)
+	cmd.Flags().StringVar(
+		&jobConfigDiffFlags.ServerB, "server-b", jobConfigDiffFlags.ServerB,
+		"Address of the Jenkins server used for the second job, with the credentials of its context",
+	)
+	cmd.Flags().StringVar(
+		&jobConfigDiffFlags.File, "file", jobConfigDiffFlags.File,
+		"Compare the job with this local config.xml instead of a second job",
+	)
+	cmd.Flags().BoolVar(
+		&jobConfigDiffFlags.IgnorePluginVersions, "ignore-plugin-versions",
+		jobConfigDiffFlags.IgnorePluginVersions,
+		"Ignore the version of the plugins in the plugin attributes",
+	)
+	cmd.Flags().IntVarP(
+		&jobConfigDiffFlags.Unified, "unified", "U", jobConfigDiffFlags.Unified,
+		"Number of context lines around each difference",
+	)
+	return cmd
+}
+
+// newConfigSource returns the source of a job on the Jenkins server of the
+// given context or address, or on the default server when both are empty,
+// the client of the command line being then connected.
+func newConfigSource(
+	client *apiclient.ApiClient, job string, contextName string, server string,
+) (*jobs.ConfigSource, error) {
+	source := &jobs.ConfigSource{Client: client, Job: job}
+	if contextName == "" && server == "" {
+		if client.Jenkins == nil {
+			if err := cmdutil.ConnectClient(client); err != nil {
+				return nil, err
+			}
+		}
+		return source, nil
+	}
+	contextClient, err := cmdutil.NewContextClient(contextName, server)
+	if err != nil {
+		return nil, err
+	}
+	source.Client = contextClient
+	source.Label = contextName
+	if server != "" {
+		source.Label = server
+	}
+	return source, nil
+}
+
+func valueOrDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func jobConfigDiff(
+	client *apiclient.ApiClient, flags *JobConfigDiffFlags, args []string,
+) error {
+	if flags.File == "" && len(args) != 2 {
+		return errors.New("two jobs or a job and a --file are required")
+	}
+	if flags.File != "" && len(args) != 1 {
+		return errors.New("only one job can be compared with a --file")
+	}
+
+	from, err := newConfigSource(
+		client, args[0],
+		valueOrDefault(flags.ContextA, flags.Context),
+		valueOrDefault(flags.ServerA, flags.Server),
+	)
+	if err != nil {
+		return err
+	}
+	to := &jobs.ConfigSource{File: flags.File}
+	if flags.File == "" {
+		to, err = newConfigSource(
+			client, args[1],
+			valueOrDefault(flags.ContextB, flags.Context),
+			valueOrDefault(flags.ServerB, flags.Server),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	configDiff, err := jobs.DiffConfigs(from, to, flags.IgnorePluginVersions, flags.Unified)
+	if err != nil {
+		return err
+	}
+	if configDiff == "" {
+		fmt.Println("configurations are identical")
+		return nil
+	}
+	fmt.Print(configDiff)
+	return nil
+}
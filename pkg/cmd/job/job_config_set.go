@@ -0,0 +1,54 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+func NewJobConfigSetCmd(client *apiclient.ApiClient) *cobra.Command {
+	jobConfigSetFlags := newJobConfigEditFlags()
+
+	// cmd represents the job config set command
+	var cmd = &cobra.Command{
+		Use:   "set",
+		Short: "set an element of the jobs configuration",
+		Long: `This command will set the text of an element (or an attribute) of the config.xml of jobs
+For example:
+	jenkinsctl job config set --xpath=//logRotator/numToKeep --value=10
+	jenkinsctl job config set --xpath=/flow-definition/disabled --value=true --name=my-app
+	jenkinsctl job config set --xpath=//assignedNode --value=linux --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			edit := jobs.ConfigEdit{
+				XPath: jobConfigSetFlags.XPath,
+				Value: jobConfigSetFlags.Value,
+			}
+			return jobConfigEdit(client, jobConfigSetFlags, &edit)
+		},
+	}
+
+	addJobConfigEditFlags(cmd, jobConfigSetFlags)
+	cmd.Flags().StringVar(
+		&jobConfigSetFlags.Value, "value", jobConfigSetFlags.Value,
+		"Value to set on the matching elements",
+	)
+	cmd.MarkFlagRequired("value")
+	return cmd
+}
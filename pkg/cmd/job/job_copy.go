@@ -0,0 +1,56 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+
+	"github.com/spf13/cobra"
+)
+
+func NewJobCopyCmd(client *apiclient.ApiClient) *cobra.Command {
+	dryRun := jobs.DRY_RUN_NONE
+
+	// cmd represents the job copy command
+	var cmd = &cobra.Command{
+		Use:   "copy <source> <destination>",
+		Short: "copy a job",
+		Long: `This command will create a job with the configuration of another job
+For example:
+	jenkinsctl job copy my-app my-app-copy
+	jenkinsctl job copy my-folder/my-app other-folder/my-app
+	jenkinsctl job copy my-app my-app-copy --dry-run`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdutil.WarnClientOnlyDryRun(dryRun)
+			result, err := jobs.Copy(client, args[0], args[1], dryRun)
+			if err != nil {
+				return err
+			}
+			cmdutil.PrintActionResults([]jobs.ActionResult{result})
+			return nil
+		},
+	}
+
+	cmdutil.AddDryRunFlag(
+		cmd, &dryRun,
+		"Only check that the job can be copied, Jenkins having no validation for the server mode",
+	)
+	return cmd
+}
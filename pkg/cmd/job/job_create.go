@@ -0,0 +1,82 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"io/ioutil"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+
+	"github.com/spf13/cobra"
+)
+
+type JobCreateFlags struct {
+	File   string
+	DryRun string
+}
+
+func newJobCreateFlags() *JobCreateFlags {
+	return &JobCreateFlags{
+		File:   "",
+		DryRun: jobs.DRY_RUN_NONE,
+	}
+}
+
+func NewJobCreateCmd(client *apiclient.ApiClient) *cobra.Command {
+	jobCreateFlags := newJobCreateFlags()
+
+	// cmd represents the job create command
+	var cmd = &cobra.Command{
+		Use:   "create <name>",
+		Short: "create a job",
+		Long: `This command will create a job from a config.xml file
+For example:
+	jenkinsctl job create my-app -f config.xml
+	jenkinsctl job create my-folder/my-app -f config.xml
+	jenkinsctl job create my-app -f config.xml --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobCreate(client, jobCreateFlags, args[0])
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&jobCreateFlags.File, "file", "f", jobCreateFlags.File,
+		"Path of the config.xml of the job",
+	)
+	cmdutil.AddDryRunFlag(
+		cmd, &jobCreateFlags.DryRun,
+		"Only check the config.xml and the name of the job, Jenkins having no validation for the server mode",
+	)
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func jobCreate(client *apiclient.ApiClient, flags *JobCreateFlags, name string) error {
+	config, err := ioutil.ReadFile(flags.File)
+	if err != nil {
+		return err
+	}
+	cmdutil.WarnClientOnlyDryRun(flags.DryRun)
+	result, err := jobs.Create(client, name, string(config), flags.DryRun)
+	if err != nil {
+		return err
+	}
+	cmdutil.PrintActionResults([]jobs.ActionResult{result})
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type JobDeleteFlags struct {
+	cmdutil.JobSelectionFlags
+	JobAgeFlags
+	Status         string
+	IncludeRunning bool
+	ForceDelete    bool
+	DryRun         string
+}
+
+func newJobDeleteFlags() *JobDeleteFlags {
+	return &JobDeleteFlags{
+		Status:         "all",
+		IncludeRunning: false,
+		ForceDelete:    false,
+		DryRun:         jobs.DRY_RUN_NONE,
+	}
+}
+
+func NewJobDeleteCmd(client *apiclient.ApiClient) *cobra.Command {
+	jobDeleteFlags := newJobDeleteFlags()
+
+	// cmd represents the job delete command
+	var cmd = &cobra.Command{
+		Use:   "delete",
+		Short: "delete jobs",
+		Long: `This command will delete jobs, the jobs having a running build are only deleted with
+--include-running and the folders are never deleted
+For example:
+	jenkinsctl job delete --name=my-app
+	jenkinsctl job delete --name=my-folder/my-app
+	jenkinsctl job delete --status=failure --minimum-age=43200
+	jenkinsctl job delete --status=failure --minimum-age=43200 --dry-run
+	jenkinsctl job delete --name=my-app --include-running --force`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobDelete(client, jobDeleteFlags)
+		},
+	}
+
+	cmd.Flags().SortFlags = false
+	cmdutil.AddJobSelectionFlags(cmd, &jobDeleteFlags.JobSelectionFlags)
+	cmd.Flags().StringVar(
+		&jobDeleteFlags.Status, "status", jobDeleteFlags.Status,
+		statusFlagUsage,
+	)
+	addJobAgeFlags(cmd, &jobDeleteFlags.JobAgeFlags)
+	cmd.Flags().BoolVar(
+		&jobDeleteFlags.IncludeRunning, "include-running", jobDeleteFlags.IncludeRunning,
+		"Also delete the jobs having a running build",
+	)
+	cmd.Flags().BoolVar(
+		&jobDeleteFlags.ForceDelete, "force", jobDeleteFlags.ForceDelete,
+		"Do not ask for confirmation",
+	)
+	cmdutil.AddDryRunFlag(
+		cmd, &jobDeleteFlags.DryRun,
+		"Only show the jobs which would be deleted, server also checking that they still exist on Jenkins",
+	)
+	return cmd
+}
+
+func jobDelete(client *apiclient.ApiClient, flags *JobDeleteFlags) error {
+	if flags.IsEmpty() && flags.Status == jobs.JOB_STATUS_ALL && !flags.hasAgeFilter() {
+		return errors.New("at least one filter is required to delete jobs")
+	}
+	filter := jobs.JobsFilterParams{
+		Status: flags.Status,
+	}
+	dryRun := flags.DryRun != jobs.DRY_RUN_NONE
+	if err := flags.CheckConfirmation(flags.ForceDelete || dryRun); err != nil {
+		return err
+	}
+	if err := flags.SetFilter(&filter); err != nil {
+		return err
+	}
+	flags.setAgeFilter(&filter)
+	err := checkStatusValidValue(flags.Status)
+	if err != nil {
+		return err
+	}
+	jobs := jobs.Jobs{}
+	err = jobs.GetFilteredJobs(client, &filter)
+	if err != nil {
+		return err
+	}
+	if len(jobs.Jobs) == 0 {
+		return errors.New("no job matches your rules")
+	}
+
+	fmt.Println("\nJobs to be deleted :")
+	jobs.PrintJobsTable()
+	if running := jobs.RunningJobs(); len(running) > 0 && !flags.IncludeRunning {
+		return fmt.Errorf(
+			"jobs %s have a running build, use --include-running to delete them",
+			strings.Join(running, ", "),
+		)
+	}
+	if !flags.ForceDelete && !dryRun {
+		err = cmdutil.AskUserForYesOrNo("delete")
+		if err != nil {
+			return err
+		}
+	}
+	cmdutil.PrintActionHeader("Deleting", flags.DryRun)
+	results, err := jobs.DeleteJobs(client, flags.DryRun)
+	cmdutil.PrintActionResults(results)
+	if dryRun {
+		return cmdutil.CheckDryRunResults(results, err)
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+
+	"github.com/spf13/cobra"
+)
+
+type JobDisableFlags struct {
+	cmdutil.JobSelectionFlags
+	JobAgeFlags
+	Status       string
+	Reason       string
+	ForceDisable bool
+	DryRun       string
+}
+
+func newJobDisableFlags() *JobDisableFlags {
+	return &JobDisableFlags{
+		Status:       "all",
+		Reason:       "",
+		ForceDisable: false,
+		DryRun:       jobs.DRY_RUN_NONE,
+	}
+}
+
+func NewJobDisableCmd(client *apiclient.ApiClient) *cobra.Command {
+	jobDisableFlags := newJobDisableFlags()
+
+	// cmd represents the job disable command
+	var cmd = &cobra.Command{
+		Use:   "disable",
+		Short: "disable jobs",
+		Long: `This command will disable jobs, the reason is written in the description of the jobs
+For example:
+	jenkinsctl job disable --name=my-app --reason="incident #42"
+	jenkinsctl job disable --status=failure
+	jenkinsctl job disable --status=failure --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobDisable(client, jobDisableFlags)
+		},
+	}
+
+	cmd.Flags().SortFlags = false
+	cmdutil.AddJobSelectionFlags(cmd, &jobDisableFlags.JobSelectionFlags)
+	cmd.Flags().StringVar(
+		&jobDisableFlags.Status, "status", jobDisableFlags.Status,
+		statusFlagUsage,
+	)
+	addJobAgeFlags(cmd, &jobDisableFlags.JobAgeFlags)
+	cmd.Flags().StringVar(
+		&jobDisableFlags.Reason, "reason", jobDisableFlags.Reason,
+		"Reason written in the description of the jobs",
+	)
+	cmd.Flags().BoolVar(
+		&jobDisableFlags.ForceDisable, "force", jobDisableFlags.ForceDisable,
+		"Do not ask for confirmation before disabling",
+	)
+	cmdutil.AddDryRunFlag(
+		cmd, &jobDisableFlags.DryRun,
+		"Only show the jobs which would be disabled, server also checking their state on Jenkins",
+	)
+	return cmd
+}
+
+func jobDisable(client *apiclient.ApiClient, flags *JobDisableFlags) error {
+	filter := jobs.JobsFilterParams{
+		Status: flags.Status,
+	}
+	dryRun := flags.DryRun != jobs.DRY_RUN_NONE
+	if err := flags.CheckConfirmation(flags.ForceDisable || dryRun); err != nil {
+		return err
+	}
+	if err := flags.SetFilter(&filter); err != nil {
+		return err
+	}
+	flags.setAgeFilter(&filter)
+	err := checkStatusValidValue(flags.Status)
+	if err != nil {
+		return err
+	}
+	jobs := jobs.Jobs{}
+	err = jobs.GetFilteredJobs(client, &filter)
+	if err != nil {
+		return err
+	}
+	if len(jobs.Jobs) == 0 {
+		return errors.New("no job matches your rules")
+	}
+
+	fmt.Println("\nJobs to be disabled :")
+	jobs.PrintJobsTable()
+	if !flags.ForceDisable && !dryRun {
+		err = cmdutil.AskUserForYesOrNo("disable")
+		if err != nil {
+			return err
+		}
+	}
+	cmdutil.PrintActionHeader("Disabling", flags.DryRun)
+	results, err := jobs.DisableJobs(client, flags.Reason, flags.DryRun)
+	cmdutil.PrintActionResults(results)
+	if dryRun {
+		return cmdutil.CheckDryRunResults(results, err)
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+
+	"github.com/spf13/cobra"
+)
+
+type JobEnableFlags struct {
+	cmdutil.JobSelectionFlags
+	JobAgeFlags
+	ForceEnable bool
+	DryRun      string
+}
+
+func newJobEnableFlags() *JobEnableFlags {
+	return &JobEnableFlags{
+		ForceEnable: false,
+		DryRun:      jobs.DRY_RUN_NONE,
+	}
+}
+
+func NewJobEnableCmd(client *apiclient.ApiClient) *cobra.Command {
+	jobEnableFlags := newJobEnableFlags()
+
+	// cmd represents the job enable command
+	var cmd = &cobra.Command{
+		Use:   "enable",
+		Short: "enable jobs",
+		Long: `This command will enable disabled jobs and remove the disable reason from their description
+For example:
+	jenkinsctl job enable --name=my-app
+	jenkinsctl job enable
+	jenkinsctl job enable --dry-run=server`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobEnable(client, jobEnableFlags)
+		},
+	}
+
+	cmd.Flags().SortFlags = false
+	cmdutil.AddJobSelectionFlags(cmd, &jobEnableFlags.JobSelectionFlags)
+	addJobAgeFlags(cmd, &jobEnableFlags.JobAgeFlags)
+	cmd.Flags().BoolVar(
+		&jobEnableFlags.ForceEnable, "force", jobEnableFlags.ForceEnable,
+		"Do not ask for confirmation before enabling",
+	)
+	cmdutil.AddDryRunFlag(
+		cmd, &jobEnableFlags.DryRun,
+		"Only show the jobs which would be enabled, server also checking their state on Jenkins",
+	)
+	return cmd
+}
+
+func jobEnable(client *apiclient.ApiClient, flags *JobEnableFlags) error {
+	filter := jobs.JobsFilterParams{
+		Status: jobs.JOB_STATUS_DISABLED,
+	}
+	dryRun := flags.DryRun != jobs.DRY_RUN_NONE
+	if err := flags.CheckConfirmation(flags.ForceEnable || dryRun); err != nil {
+		return err
+	}
+	if err := flags.SetFilter(&filter); err != nil {
+		return err
+	}
+	flags.setAgeFilter(&filter)
+	jobs := jobs.Jobs{}
+	err := jobs.GetFilteredJobs(client, &filter)
+	if err != nil {
+		return err
+	}
+
+	if len(jobs.Jobs) == 0 {
+		fmt.Println("all jobs are in enabled state")
+		return nil
+	}
+
+	fmt.Println("\nJobs to be enabled :")
+	jobs.PrintJobsTable()
+	if !flags.ForceEnable && !dryRun {
+		err = cmdutil.AskUserForYesOrNo("enable")
+		if err != nil {
+			return err
+		}
+	}
+	cmdutil.PrintActionHeader("Enabling", flags.DryRun)
+	results, err := jobs.EnableJobs(client, flags.DryRun)
+	cmdutil.PrintActionResults(results)
+	if dryRun {
+		return cmdutil.CheckDryRunResults(results, err)
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package job
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type JobInputSubmitFlags struct {
+	Build  int64
+	ID     string
+	Params []string
+	DryRun string
+	Force  bool
+}
+
+func NewJobInputCmd(client *apiclient.ApiClient) *cobra.Command {
+
+	// cmd represents the job input command
+	var cmd = &cobra.Command{
+		Use:   "input",
+		Short: "manage the input steps of pipelines",
+		Long: `This command allows to list, approve and abort the input steps waiting in pipeline builds
+
+For example:
+	jenkinsctl job input list
+	jenkinsctl job input approve deploy-prod --param=VERSION=1.2.3
+	jenkinsctl job input abort deploy-prod --build=42`,
+	}
+
+	cmd.AddCommand(NewJobInputListCmd(client))
+	cmd.AddCommand(NewJobInputApproveCmd(client))
+	cmd.AddCommand(NewJobInputAbortCmd(client))
+	return cmd
+}
+
+func addJobInputSubmitFlags(cmd *cobra.Command, flags *JobInputSubmitFlags) {
+	cmd.Flags().Int64Var(
+		&flags.Build, "build", flags.Build,
+		"Number of the build, the last build by default",
+	)
+	cmd.Flags().StringVar(
+		&flags.ID, "id", flags.ID,
+		"Id of the input, needed when the build waits for several inputs",
+	)
+	cmdutil.AddDryRunFlag(
+		cmd, &flags.DryRun,
+		"Only show the input which would be submitted, Jenkins having no validation for the server mode",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Force, "force", flags.Force,
+		"Do not ask for confirmation before submitting the input",
+	)
+}
+
+// confirmInputSubmit asks the user to confirm the action on the input, unless
+// forced.
+func confirmInputSubmit(input *jobs.PendingInput, action string, flags *JobInputSubmitFlags) error {
+	if flags.Force {
+		return nil
+	}
+	return cmdutil.AskUserForConfirmation(fmt.Sprintf(
+		"Do you want to %s the input %s of %s #%s ?", action, input.ID, input.Run.Job, input.Run.ID,
+	))
+}
+
+// getPendingInput returns the input step waiting in the build of the job
+// designated by the flags.
+func getPendingInput(
+	client *apiclient.ApiClient, name string, flags *JobInputSubmitFlags,
+) (*jobs.PendingInput, error) {
+	if flags.Build < 0 {
+		return nil, fmt.Errorf("invalid build number %d", flags.Build)
+	}
+	run, err := jobs.GetPipelineRun(client, name, flags.Build)
+	if err != nil {
+		return nil, err
+	}
+	return run.GetPendingInput(client, flags.ID)
+}
+
+// parseInputParams parses the values of the --param flags, given as
+// NAME=VALUE.
+func parseInputParams(params []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, param := range params {
+		i := strings.Index(param, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid parameter %s, expected NAME=VALUE", param)
+		}
+		values[param[:i]] = param[i+1:]
+	}
+	return values, nil
+}
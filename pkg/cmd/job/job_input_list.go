@@ -0,0 +1,70 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package job
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+
+	"github.com/spf13/cobra"
+)
+
+func NewJobInputListCmd(client *apiclient.ApiClient) *cobra.Command {
+	selectionFlags := &cmdutil.JobSelectionFlags{}
+
+	// cmd represents the job input list command
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Short: "list the input steps waiting for an approval",
+		Long: `This command will list the input steps waiting in the recent builds of the pipeline jobs
+For example:
+	jenkinsctl job input list
+	jenkinsctl job input list --name='deploy-*'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter := jobs.JobsFilterParams{
+				Status: jobs.JOB_STATUS_ALL,
+			}
+			if err := selectionFlags.SetFilter(&filter); err != nil {
+				return err
+			}
+			var listed jobs.Jobs
+			err := listed.GetFilteredJobs(client, &filter)
+			if err != nil {
+				return err
+			}
+			if len(listed.Jobs) == 0 {
+				return errors.New("no job matches your rules")
+			}
+			inputs, err := listed.GetPendingInputs(client)
+			if err != nil {
+				return err
+			}
+			if len(inputs) == 0 {
+				fmt.Println("No input step is waiting")
+				return nil
+			}
+			jobs.PrintPendingInputsTable(inputs)
+			return nil
+		},
+	}
+
+	cmd.Flags().SortFlags = false
+	cmdutil.AddJobSelectionFlags(cmd, selectionFlags)
+	return cmd
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package job
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+
+	"github.com/spf13/cobra"
+)
+
+func NewJobInputApproveCmd(client *apiclient.ApiClient) *cobra.Command {
+	flags := &JobInputSubmitFlags{}
+
+	// cmd represents the job input approve command
+	var cmd = &cobra.Command{
+		Use:   "approve <name>",
+		Short: "approve an input step",
+		Long: `This command will approve the input step waiting in a build, the last build by
+default, the parameters not given keeping their default value
+For example:
+	jenkinsctl job input approve deploy-prod
+	jenkinsctl job input approve deploy-prod --build=42 --param=VERSION=1.2.3
+	jenkinsctl job input approve deploy-prod --param=VERSION=1.2.3 --dry-run
+	jenkinsctl job input approve deploy-prod --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			values, err := parseInputParams(flags.Params)
+			if err != nil {
+				return err
+			}
+			input, err := getPendingInput(client, args[0], flags)
+			if err != nil {
+				return err
+			}
+			if flags.DryRun != jobs.DRY_RUN_NONE {
+				parameters, err := input.ApprovalParameters(values)
+				if err != nil {
+					return err
+				}
+				if parameters == "" {
+					parameters = "no parameter"
+				}
+				fmt.Printf(
+					"Input %s of %s #%s would be approved with %s\n", input.ID, input.Run.Job, input.Run.ID, parameters,
+				)
+				cmdutil.WarnClientOnlyDryRun(flags.DryRun)
+				return nil
+			}
+			if err := confirmInputSubmit(input, "approve", flags); err != nil {
+				return err
+			}
+			err = input.Approve(client, values)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Input %s of %s #%s approved\n", input.ID, input.Run.Job, input.Run.ID)
+			return nil
+		},
+	}
+
+	addJobInputSubmitFlags(cmd, flags)
+	cmd.Flags().StringArrayVar(
+		&flags.Params, "param", flags.Params,
+		"Value of a parameter of the input as NAME=VALUE, the flag can be repeated",
+	)
+	return cmd
+}
+
+func NewJobInputAbortCmd(client *apiclient.ApiClient) *cobra.Command {
+	flags := &JobInputSubmitFlags{}
+
+	// cmd represents the job input abort command
+	var cmd = &cobra.Command{
+		Use:   "abort <name>",
+		Short: "abort an input step",
+		Long: `This command will reject the input step waiting in a build, the last build by
+default, which aborts the build
+For example:
+	jenkinsctl job input abort deploy-prod
+	jenkinsctl job input abort deploy-prod --build=42
+	jenkinsctl job input abort deploy-prod --dry-run
+	jenkinsctl job input abort deploy-prod --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input, err := getPendingInput(client, args[0], flags)
+			if err != nil {
+				return err
+			}
+			if flags.DryRun != jobs.DRY_RUN_NONE {
+				fmt.Printf("Input %s of %s #%s would be aborted\n", input.ID, input.Run.Job, input.Run.ID)
+				cmdutil.WarnClientOnlyDryRun(flags.DryRun)
+				return nil
+			}
+			if err := confirmInputSubmit(input, "abort", flags); err != nil {
+				return err
+			}
+			err = input.Abort(client)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Input %s of %s #%s aborted\n", input.ID, input.Run.Job, input.Run.ID)
+			return nil
+		},
+	}
+
+	addJobInputSubmitFlags(cmd, flags)
+	return cmd
+}
@@ -16,26 +16,43 @@ limitations under the License.
 package job
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"jenkinsctl/pkg/apiclient"
 	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"jenkinsctl/pkg/jenkinsctl"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
 type JobListFlags struct {
-	Name   string
-	AgeMin int
-	AgeMax int
-	Status string
+	cmdutil.JobSelectionFlags
+	JobAgeFlags
+	Status   string
+	SortBy   string
+	Reverse  bool
+	Limit    int
+	Columns  []string
+	Watch    bool
+	Interval time.Duration
 }
 
 func newJobListFlags() *JobListFlags {
 	return &JobListFlags{
-		Name:   "",
-		AgeMin: 0,
-		AgeMax: 0,
-		Status: "all",
+		Status:   "all",
+		SortBy:   "",
+		Reverse:  false,
+		Limit:    0,
+		Columns:  jobs.DefaultJobColumns,
+		Watch:    false,
+		Interval: 5 * time.Second,
 	}
 }
 
@@ -49,50 +66,198 @@ func NewJobListCmd(client *apiclient.ApiClient) *cobra.Command {
 		Long: `This command will list an jobs on Jenkins
 For example:
 	jenkinsctl job list
-	jenkinsctl job list --state=running --minimum-age=30 --maximum-age=3600
-	jenkinsctl job list --name=my-app`,
+	jenkinsctl job list --status=running --minimum-age=30m --maximum-age=2d
+	jenkinsctl job list --since=yesterday --until='2h ago'
+	jenkinsctl job list --name=my-app
+	jenkinsctl job list --name='deploy-*' --exclude=deploy-prod
+	jenkinsctl job list --sort-by=duration --reverse --limit=10 --columns=name,duration,node,cause
+	jenkinsctl job list --status=running --watch --interval=10s`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return jobList(client, jobListFlags)
 		},
 	}
 	cmd.Flags().SortFlags = false
+	cmdutil.AddJobSelectionFlags(cmd, &jobListFlags.JobSelectionFlags)
 	cmd.Flags().StringVar(
-		&jobListFlags.Name, "name", jobListFlags.Name, "Specify the name of the job",
+		&jobListFlags.Status, "status", jobListFlags.Status,
+		statusFlagUsage,
 	)
+	addJobAgeFlags(cmd, &jobListFlags.JobAgeFlags)
 	cmd.Flags().StringVar(
-		&jobListFlags.Status, "status", jobListFlags.Status,
-		"Filter Job from status (possible values: all, running, success, aborted, failure)",
+		&jobListFlags.SortBy, "sort-by", jobListFlags.SortBy,
+		"Sort Jobs (possible values: "+strings.Join(jobs.JobSortKeys, ", ")+")",
 	)
-	cmd.Flags().IntVar(
-		&jobListFlags.AgeMin, "minimum-age", jobListFlags.AgeMin,
-		"Filter Jobs from last build minimum age (in minutes)",
+	cmd.Flags().BoolVar(
+		&jobListFlags.Reverse, "reverse", jobListFlags.Reverse,
+		"Reverse the order of the Jobs",
 	)
 	cmd.Flags().IntVar(
-		&jobListFlags.AgeMin, "maximum-age", jobListFlags.AgeMin,
-		"Filter Jobs from last build maximum age (in minutes)",
+		&jobListFlags.Limit, "limit", jobListFlags.Limit,
+		"Maximum number of Jobs to list, after sorting (0 for no limit)",
+	)
+	cmd.Flags().StringSliceVar(
+		&jobListFlags.Columns, "columns", jobListFlags.Columns,
+		"Columns to print (possible values: "+strings.Join(jobs.JobColumns, ", ")+")",
+	)
+	cmd.Flags().BoolVarP(
+		&jobListFlags.Watch, "watch", "w", jobListFlags.Watch,
+		"Poll the Jobs every interval, redraw the table on a terminal and print the status changes otherwise",
+	)
+	cmd.Flags().DurationVar(
+		&jobListFlags.Interval, "interval", jobListFlags.Interval,
+		"Interval between two polls with --watch",
 	)
 	return cmd
 }
 
 func jobList(client *apiclient.ApiClient, flags *JobListFlags) error {
-	filter := jobs.JobsFilterParams{
-		Name:   flags.Name,
-		AgeMin: flags.AgeMin,
-		AgeMax: flags.AgeMax,
+	filter := jenkinsctl.Filter{
 		Status: flags.Status,
 	}
+	if err := flags.SetLibraryFilter(&filter); err != nil {
+		return err
+	}
+	flags.setLibraryAgeFilter(&filter)
 	err := checkStatusValidValue(flags.Status)
 	if err != nil {
 		return err
 	}
-	var jobs jobs.Jobs
-	err = jobs.GetFilteredJobs(client, &filter)
+	err = jobs.CheckSortKey(flags.SortBy)
+	if err != nil {
+		return err
+	}
+	err = jobs.CheckColumns(flags.Columns)
 	if err != nil {
 		return err
 	}
-	if len(jobs.Jobs) == 0 {
+	if flags.Limit < 0 {
+		return errors.New("--limit must be positive")
+	}
+	if flags.Interval <= 0 {
+		return errors.New("--interval must be positive")
+	}
+	library, err := cmdutil.NewLibraryClient(client, jobs.DRY_RUN_NONE)
+	if err != nil {
+		return err
+	}
+	if flags.Watch {
+		return jobListWatch(library, flags, filter)
+	}
+
+	listed, err := listJobs(context.Background(), library, flags, filter)
+	if err != nil {
+		return err
+	}
+	listed = jobs.LimitSummaries(listed, flags.Limit)
+	if len(listed) == 0 {
 		return errors.New("no job matches your rules")
 	}
-	jobs.PrintJobsTable()
+	jobs.PrintSummariesTable(listed, flags.Columns, nil)
 	return nil
 }
+
+// listJobs gets the jobs matching the filter, sorted as asked.
+func listJobs(
+	ctx context.Context, library *jenkinsctl.Client, flags *JobListFlags, filter jenkinsctl.Filter,
+) ([]jobs.JobSummary, error) {
+	listed, err := library.Jobs().List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	err = jobs.SortSummaries(listed, flags.SortBy, flags.Reverse)
+	if err != nil {
+		return nil, err
+	}
+	return listed, nil
+}
+
+func jobNames(listed []jobs.JobSummary) map[string]bool {
+	names := map[string]bool{}
+	for i := range listed {
+		names[listed[i].Name] = true
+	}
+	return names
+}
+
+// shownChanges keeps the changes of the jobs shown and of the jobs shown
+// before. The changes being computed on the listings before the status filter
+// and the limit, a job leaving the status filter is reported with its new
+// status, and a job pushed out of the limit by the others is not reported.
+func shownChanges(
+	changes []jobs.JobStatusChange, shown []jobs.JobSummary, previouslyShown []jobs.JobSummary,
+) []jobs.JobStatusChange {
+	names := jobNames(shown)
+	previousNames := jobNames(previouslyShown)
+	kept := []jobs.JobStatusChange{}
+	for _, change := range changes {
+		if names[change.Name] || previousNames[change.Name] {
+			kept = append(kept, change)
+		}
+	}
+	return kept
+}
+
+// filterStatus returns the jobs of the listing matching the status filter.
+func filterStatus(listed []jobs.JobSummary, statusFilter *jobs.StatusFilter) []jobs.JobSummary {
+	matching := []jobs.JobSummary{}
+	for i := range listed {
+		if statusFilter.MatchSummary(&listed[i]) {
+			matching = append(matching, listed[i])
+		}
+	}
+	return matching
+}
+
+// jobListWatch lists the jobs every interval until interrupted. On a terminal
+// the table is redrawn with the jobs whose status changed highlighted,
+// otherwise only the changes are printed so that the output can feed logs.
+func jobListWatch(library *jenkinsctl.Client, flags *JobListFlags, filter jenkinsctl.Filter) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The jobs are listed whatever their status, so that the changes of the
+	// jobs leaving the status filter are known.
+	statusFilter, err := jobs.ParseStatusFilter(filter.Status)
+	if err != nil {
+		return err
+	}
+	filter.Status = ""
+
+	terminal := cmdutil.IsTerminal(os.Stdout)
+	var previous, previouslyShown []jobs.JobSummary
+	listedOnce := false
+	for {
+		listed, err := listJobs(ctx, library, flags, filter)
+		if ctx.Err() != nil {
+			return nil
+		}
+		shown := jobs.LimitSummaries(filterStatus(listed, statusFilter), flags.Limit)
+		switch {
+		case err != nil && terminal:
+			fmt.Print("\033[H\033[2J")
+			fmt.Printf("Every %s, last update at %s\n\nerror: %s\n", flags.Interval, time.Now().Format("15:04:05"), err)
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		case terminal:
+			var changes []jobs.JobStatusChange
+			if listedOnce {
+				changes = shownChanges(jobs.StatusChanges(listed, previous), shown, previouslyShown)
+			}
+			fmt.Print("\033[H\033[2J")
+			fmt.Printf("Every %s, last update at %s\n\n", flags.Interval, time.Now().Format("15:04:05"))
+			jobs.PrintSummariesTable(shown, flags.Columns, changes)
+		default:
+			for _, change := range shownChanges(jobs.StatusChanges(listed, previous), shown, previouslyShown) {
+				fmt.Println(change)
+			}
+		}
+		if err == nil {
+			previous, previouslyShown, listedOnce = listed, shown, true
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(flags.Interval):
+		}
+	}
+}
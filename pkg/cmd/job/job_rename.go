@@ -0,0 +1,56 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+
+	"github.com/spf13/cobra"
+)
+
+func NewJobRenameCmd(client *apiclient.ApiClient) *cobra.Command {
+	dryRun := jobs.DRY_RUN_NONE
+
+	// cmd represents the job rename command
+	var cmd = &cobra.Command{
+		Use:   "rename <name> <new-name>",
+		Short: "rename a job",
+		Long: `This command will rename a job, the job stays in its folder
+For example:
+	jenkinsctl job rename my-app my-new-app
+	jenkinsctl job rename my-folder/my-app my-new-app
+	jenkinsctl job rename my-app my-new-app --dry-run`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdutil.WarnClientOnlyDryRun(dryRun)
+			result, err := jobs.Rename(client, args[0], args[1], dryRun)
+			if err != nil {
+				return err
+			}
+			cmdutil.PrintActionResults([]jobs.ActionResult{result})
+			return nil
+		},
+	}
+
+	cmdutil.AddDryRunFlag(
+		cmd, &dryRun,
+		"Only check that the job can be renamed, Jenkins having no validation for the server mode",
+	)
+	return cmd
+}
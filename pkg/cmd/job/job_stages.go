@@ -0,0 +1,74 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+func NewJobStagesCmd(client *apiclient.ApiClient) *cobra.Command {
+
+	var build int64
+	var failedOnly bool
+
+	// cmd represents the job stages command
+	var cmd = &cobra.Command{
+		Use:   "stages <name>",
+		Short: "show the stages of a pipeline build",
+		Long: `This command will show the status, the duration and the pause time of the
+stages of a pipeline build, the last build by default
+For example:
+	jenkinsctl job stages my-app
+	jenkinsctl job stages my-folder/my-app --build=42
+	jenkinsctl job stages my-app --failed-only`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if build < 0 {
+				return fmt.Errorf("invalid build number %d", build)
+			}
+			run, err := jobs.GetPipelineRun(client, args[0], build)
+			if err != nil {
+				return err
+			}
+			if !failedOnly {
+				run.PrintStagesTable(run.Stages)
+				return nil
+			}
+			failed := run.FailedStages()
+			if len(failed) == 0 {
+				fmt.Printf("%s #%s: no failed stage\n", run.Job, run.ID)
+				return nil
+			}
+			run.PrintStagesTable(failed)
+			return run.PrintFailedStagesLogs(client)
+		},
+	}
+
+	cmd.Flags().Int64Var(
+		&build, "build", 0,
+		"Number of the build, the last build by default",
+	)
+	cmd.Flags().BoolVar(
+		&failedOnly, "failed-only", false,
+		"Show only the failed stages, with the log of their steps",
+	)
+	return cmd
+}
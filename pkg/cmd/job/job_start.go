@@ -16,31 +16,32 @@ limitations under the License.
 package job
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"jenkinsctl/pkg/apiclient"
 	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"jenkinsctl/pkg/jenkinsctl"
 
 	"github.com/spf13/cobra"
 )
 
 type JobStartFlags struct {
-	Name       string
-	Cron       string
-	AgeMin     int
-	AgeMax     int
+	cmdutil.JobSelectionFlags
+	Cron string
+	JobAgeFlags
 	Status     string
 	ForceStart bool
+	DryRun     string
 }
 
 func newJobStartFlags() *JobStartFlags {
 	return &JobStartFlags{
-		Name:       "",
-		AgeMin:     0,
-		AgeMax:     0,
 		Status:     "all",
 		Cron:       "",
 		ForceStart: false,
+		DryRun:     jobs.DRY_RUN_NONE,
 	}
 }
 
@@ -54,29 +55,21 @@ func NewJobStartCmd(client *apiclient.ApiClient) *cobra.Command {
 		Long: `For example:
 	jenkinsctl job start --name=my-app
 	jenkinsctl job start --minimum-age=1h
-	jenkinsctl job start --name=my-app --schedule=@daily`,
+	jenkinsctl job start --name=my-app --schedule=@daily
+	jenkinsctl job start --name='deploy-*' --dry-run
+	jenkinsctl job start --name=my-app --schedule='H 2 * * *' --dry-run=server`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return jobStart(client, jobStartFlags)
 		},
 	}
 
 	cmd.Flags().SortFlags = false
-	cmd.Flags().StringVar(
-		&jobStartFlags.Name, "name", jobStartFlags.Name,
-		"Filter Jobs from the name",
-	)
+	cmdutil.AddJobSelectionFlags(cmd, &jobStartFlags.JobSelectionFlags)
 	cmd.Flags().StringVar(
 		&jobStartFlags.Status, "status", jobStartFlags.Status,
-		"Filter Jobs from status (possible values: all, running, success, aborted, failure)",
-	)
-	cmd.Flags().IntVar(
-		&jobStartFlags.AgeMin, "minimum-age", jobStartFlags.AgeMin,
-		"Filter Jobs from last build minimum age (in minutes)",
-	)
-	cmd.Flags().IntVar(
-		&jobStartFlags.AgeMin, "maximum-age", jobStartFlags.AgeMax,
-		"Filter Jobs from last build maximum age (in minutes)",
+		statusFlagUsage,
 	)
+	addJobAgeFlags(cmd, &jobStartFlags.JobAgeFlags)
 	cmd.Flags().StringVar(
 		&jobStartFlags.Cron, "schedule", jobStartFlags.Cron,
 		"Specify the schedule in Jenkins time trigger syntax",
@@ -85,23 +78,37 @@ func NewJobStartCmd(client *apiclient.ApiClient) *cobra.Command {
 		&jobStartFlags.ForceStart, "force", jobStartFlags.ForceStart,
 		"Force stop jobs",
 	)
+	cmdutil.AddDryRunFlag(
+		cmd, &jobStartFlags.DryRun,
+		"Only show the parameters of the builds or the config.xml diff of the schedules, "+
+			"server also validating the jobs and the schedule on Jenkins",
+	)
 	return cmd
 }
 
 func jobStart(client *apiclient.ApiClient, flags *JobStartFlags) error {
-	filter := jobs.JobsFilterParams{
-		Name:   flags.Name,
-		AgeMin: flags.AgeMin,
-		AgeMax: flags.AgeMax,
+	filter := jenkinsctl.Filter{
 		Status: flags.Status,
 	}
+	dryRun := flags.DryRun != jobs.DRY_RUN_NONE
+	if err := flags.CheckConfirmation(flags.ForceStart || dryRun); err != nil {
+		return err
+	}
+	if err := flags.SetLibraryFilter(&filter); err != nil {
+		return err
+	}
+	flags.setLibraryAgeFilter(&filter)
 
-	var jobs jobs.Jobs
-	err := jobs.GetFilteredJobs(client, &filter)
+	library, err := cmdutil.NewLibraryClient(client, flags.DryRun)
 	if err != nil {
 		return err
 	}
-	if len(jobs.Jobs) == 0 {
+	ctx := context.Background()
+	listed, err := library.Jobs().List(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if len(listed) == 0 {
 		return errors.New("no job matches your rules")
 	}
 
@@ -113,15 +120,24 @@ func jobStart(client *apiclient.ApiClient, flags *JobStartFlags) error {
 		action = "start"
 		fmt.Println("\nJobs to be started :")
 	}
-	jobs.PrintJobsTable()
-	if !flags.ForceStart {
-		err = askUserForYesOrNo(action)
+	jobs.PrintSummariesTable(listed, jobs.DefaultJobColumns, nil)
+	if !flags.ForceStart && !dryRun {
+		err = cmdutil.AskUserForYesOrNo(action)
 		if err != nil {
 			return err
 		}
 	}
+	confirmed := jenkinsctl.Filter{Names: cmdutil.ExactNames(listed)}
+	var results []jobs.ActionResult
 	if action == "schedule" {
-		return jobs.Schedule(client, flags.Cron)
+		cmdutil.PrintActionHeader("Scheduling", flags.DryRun)
+		results, err = library.Jobs().Schedule(ctx, confirmed, flags.Cron)
+	} else {
+		cmdutil.PrintActionHeader("Starting", flags.DryRun)
+		results, err = library.Jobs().Start(ctx, confirmed)
+	}
+	if dryRun {
+		return cmdutil.CheckDryRunResults(results, err)
 	}
-	return jobs.Start(client)
+	return err
 }
@@ -16,26 +16,27 @@ limitations under the License.
 package job
 
 import (
+	"context"
 	"fmt"
 	"jenkinsctl/pkg/apiclient"
 	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"jenkinsctl/pkg/jenkinsctl"
 
 	"github.com/spf13/cobra"
 )
 
 type JobStopFlags struct {
-	Name      string
-	AgeMin    int
-	AgeMax    int
+	cmdutil.JobSelectionFlags
+	JobAgeFlags
 	ForceStop bool
+	DryRun    string
 }
 
 func newJobStopFlags() *JobStopFlags {
 	return &JobStopFlags{
-		Name:      "",
-		AgeMin:    0,
-		AgeMax:    0,
 		ForceStop: false,
+		DryRun:    jobs.DRY_RUN_NONE,
 	}
 }
 
@@ -49,57 +50,68 @@ func NewJobStopCmd(client *apiclient.ApiClient) *cobra.Command {
 		Short: "stop jobs",
 		Long: `For example:
 	jenkinsctl job stop --minimum-age=1h
-	jenkinsctl job stop --name=my-app`,
+	jenkinsctl job stop --name=my-app
+	jenkinsctl job stop --regex='^app-(api|web)$'
+	jenkinsctl job stop --minimum-age=1h --dry-run=server`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return jobStop(client, jobStopFlags)
 		},
 	}
 
 	cmd.Flags().SortFlags = false
-	cmd.Flags().StringVar(
-		&jobStopFlags.Name, "name", jobStopFlags.Name,
-		"Filter Jobs from the name",
-	)
-	cmd.Flags().IntVar(
-		&jobStopFlags.AgeMin, "minimum-age", jobStopFlags.AgeMin,
-		"Filter Jobs from last build minimum age (in minutes)",
-	)
-	cmd.Flags().IntVar(
-		&jobStopFlags.AgeMax, "maximum-age", jobStopFlags.AgeMax,
-		"Filter Jobs from last build maximum age (in minutes)",
-	)
+	cmdutil.AddJobSelectionFlags(cmd, &jobStopFlags.JobSelectionFlags)
+	addJobAgeFlags(cmd, &jobStopFlags.JobAgeFlags)
 	cmd.Flags().BoolVar(
 		&jobStopFlags.ForceStop, "force", jobStopFlags.ForceStop,
 		"Force stop jobs",
 	)
+	cmdutil.AddDryRunFlag(
+		cmd, &jobStopFlags.DryRun,
+		"Only show the builds which would be stopped, server also checking that they are still running",
+	)
 	return cmd
 }
 
 func jobStop(client *apiclient.ApiClient, flags *JobStopFlags) error {
-	filter := jobs.JobsFilterParams{
-		Name:   flags.Name,
-		AgeMin: flags.AgeMin,
-		AgeMax: flags.AgeMax,
+	filter := jenkinsctl.Filter{
 		Status: jobs.JOB_STATUS_RUNNING,
 	}
-	jobs := jobs.Jobs{}
-	err := jobs.GetFilteredJobs(client, &filter)
+	dryRun := flags.DryRun != jobs.DRY_RUN_NONE
+	if err := flags.CheckConfirmation(flags.ForceStop || dryRun); err != nil {
+		return err
+	}
+	if err := flags.SetLibraryFilter(&filter); err != nil {
+		return err
+	}
+	flags.setLibraryAgeFilter(&filter)
+
+	library, err := cmdutil.NewLibraryClient(client, flags.DryRun)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	listed, err := library.Jobs().List(ctx, filter)
 	if err != nil {
 		return err
 	}
 
-	if len(jobs.Jobs) == 0 {
+	if len(listed) == 0 {
 		fmt.Println("all jobs are in stopped state")
 		return nil
 	}
 
 	fmt.Println("\nJobs to be stopped :")
-	jobs.PrintJobsTable()
-	if !flags.ForceStop {
-		err = askUserForYesOrNo("stop")
+	jobs.PrintSummariesTable(listed, jobs.DefaultJobColumns, nil)
+	if !flags.ForceStop && !dryRun {
+		err = cmdutil.AskUserForYesOrNo("stop")
 		if err != nil {
 			return err
 		}
 	}
-	return jobs.Stop(client, flags.ForceStop)
+	cmdutil.PrintActionHeader("Stopping", flags.DryRun)
+	results, err := library.Jobs().Stop(ctx, jenkinsctl.Filter{Names: cmdutil.ExactNames(listed)})
+	if dryRun {
+		return cmdutil.CheckDryRunResults(results, err)
+	}
+	return err
 }
@@ -0,0 +1,64 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package job
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func NewJobTestsCmd(client *apiclient.ApiClient) *cobra.Command {
+	build := "last"
+
+	// cmd represents the job tests command
+	var cmd = &cobra.Command{
+		Use:   "tests <name>",
+		Short: "show the test results of a build",
+		Long: `This command will show the number of passed, failed and skipped tests of a build,
+and the failed tests with their error message
+For example:
+	jenkinsctl job tests my-app
+	jenkinsctl job tests my-app --build=42
+	jenkinsctl job tests flaky my-app --builds=20`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			number, err := jobs.GetBuildNumber(client, args[0], build)
+			if err != nil {
+				return err
+			}
+			report, err := jobs.GetTestReport(client, args[0], number)
+			if err != nil {
+				if err.Error() == "404" {
+					return fmt.Errorf("%s #%d has no test report", args[0], number)
+				}
+				return err
+			}
+			report.PrintTestReport()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&build, "build", build,
+		"Number of the build, or one of "+strings.Join(jobs.BuildRefs, ", "),
+	)
+	cmd.AddCommand(NewJobTestsFlakyCmd(client))
+	return cmd
+}
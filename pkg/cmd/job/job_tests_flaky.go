@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package job
+
+import (
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var flakyTestsFormats = []string{"table", "json", "junit"}
+
+func NewJobTestsFlakyCmd(client *apiclient.ApiClient) *cobra.Command {
+	builds := 20
+	format := "table"
+
+	// cmd represents the job tests flaky command
+	var cmd = &cobra.Command{
+		Use:   "flaky <name>",
+		Short: "find the flaky tests of a job",
+		Long: `This command will analyze the test reports of the last builds of a job and list the
+tests whose outcome flips between passed and failed, the most flaky first
+For example:
+	jenkinsctl job tests flaky my-app
+	jenkinsctl job tests flaky my-app --builds=50 --format=junit > flaky.xml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if builds < 2 {
+				return errors.New("--builds must be at least 2")
+			}
+			if err := checkFlakyTestsFormat(format); err != nil {
+				return err
+			}
+			flaky, err := jobs.GetFlakyTests(client, args[0], builds)
+			if err != nil {
+				return err
+			}
+			switch format {
+			case "json":
+				return flaky.WriteJSON(os.Stdout)
+			case "junit":
+				return flaky.WriteJUnit(os.Stdout)
+			}
+			flaky.PrintFlakyTestsTable()
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(
+		&builds, "builds", builds,
+		"Number of builds to analyze",
+	)
+	cmd.Flags().StringVar(
+		&format, "format", format,
+		"Output format (possible values: "+strings.Join(flakyTestsFormats, ", ")+")",
+	)
+	return cmd
+}
+
+func checkFlakyTestsFormat(format string) error {
+	for _, accepted := range flakyTestsFormats {
+		if format == accepted {
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"%s is not accepted format (possible values: %s)", format, strings.Join(flakyTestsFormats, ", "),
+	)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package report
+
+import (
+	"jenkinsctl/pkg/apiclient"
+
+	"github.com/spf13/cobra"
+)
+
+func NewReportCmd(client *apiclient.ApiClient) *cobra.Command {
+
+	// cmd represents the report command
+	var cmd = &cobra.Command{
+		Use:   "report",
+		Short: "This command allows to build reports on the jobs",
+		Long: `This command allows to build reports on the builds of the jobs
+
+For example:
+
+show the success rate and the durations of the builds:
+	jenkinsctl report trends --window=30d
+	jenkinsctl report trends --name='deploy-*' --format=html --output=trends.html`,
+	}
+
+	cmd.AddCommand(NewReportTrendsCmd(client))
+	return cmd
+}
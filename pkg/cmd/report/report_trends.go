@@ -0,0 +1,157 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/apiclient/report"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"jenkinsctl/pkg/timeutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var trendsFormats = []string{"table", "csv", "html"}
+
+type ReportTrendsFlags struct {
+	cmdutil.JobSelectionFlags
+	Builds int
+	Window string
+	Format string
+	Output string
+}
+
+func newReportTrendsFlags() *ReportTrendsFlags {
+	return &ReportTrendsFlags{
+		Builds: 50,
+		Window: "30d",
+		Format: "table",
+		Output: "",
+	}
+}
+
+func NewReportTrendsCmd(client *apiclient.ApiClient) *cobra.Command {
+	reportTrendsFlags := newReportTrendsFlags()
+
+	// cmd represents the report trends command
+	var cmd = &cobra.Command{
+		Use:   "trends",
+		Short: "show the success rate and the durations of the builds",
+		Long: `This command will compute for each job, from its last builds started during the
+window, the success rate, the mean, median and 95th percentile durations, the
+mean time to recover from a failure and the trend of the success rate and of
+the duration between the older and the recent half of the builds
+For example:
+	jenkinsctl report trends
+	jenkinsctl report trends --name='deploy-*' --window=2w --builds=100
+	jenkinsctl report trends --format=csv > trends.csv
+	jenkinsctl report trends --format=html --output=trends.html`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return reportTrends(client, reportTrendsFlags)
+		},
+	}
+
+	cmd.Flags().SortFlags = false
+	cmdutil.AddJobSelectionFlags(cmd, &reportTrendsFlags.JobSelectionFlags)
+	cmd.Flags().IntVar(
+		&reportTrendsFlags.Builds, "builds", reportTrendsFlags.Builds,
+		"Maximum number of builds analyzed per job",
+	)
+	cmd.Flags().StringVar(
+		&reportTrendsFlags.Window, "window", reportTrendsFlags.Window,
+		"Only the builds started during the window (like 12h, 7d or 2w)",
+	)
+	cmd.Flags().StringVar(
+		&reportTrendsFlags.Format, "format", reportTrendsFlags.Format,
+		"Output format (possible values: "+strings.Join(trendsFormats, ", ")+")",
+	)
+	cmd.Flags().StringVarP(
+		&reportTrendsFlags.Output, "output", "o", reportTrendsFlags.Output,
+		"File where the report is written, stdout by default",
+	)
+	return cmd
+}
+
+func checkTrendsFormat(format string) error {
+	for _, accepted := range trendsFormats {
+		if format == accepted {
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"%s is not accepted format (possible values: %s)", format, strings.Join(trendsFormats, ", "),
+	)
+}
+
+func reportTrends(client *apiclient.ApiClient, flags *ReportTrendsFlags) error {
+	if err := checkTrendsFormat(flags.Format); err != nil {
+		return err
+	}
+	if flags.Builds <= 0 {
+		return errors.New("--builds must be positive")
+	}
+	window, err := timeutil.ParseDuration(flags.Window)
+	if err != nil {
+		return fmt.Errorf("invalid window %s: %s", flags.Window, err)
+	}
+	if window <= 0 {
+		return errors.New("--window must be positive")
+	}
+	filter := jobs.JobsFilterParams{
+		Status: jobs.JOB_STATUS_ALL,
+	}
+	if err := flags.SetFilter(&filter); err != nil {
+		return err
+	}
+	var listed jobs.Jobs
+	err = listed.GetFilteredJobs(client, &filter)
+	if err != nil {
+		return err
+	}
+	if len(listed.Jobs) == 0 {
+		return errors.New("no job matches your rules")
+	}
+
+	now := time.Now()
+	trends, err := report.GetTrends(client, &listed, flags.Builds, window, now)
+	if err != nil {
+		return err
+	}
+
+	var writer io.Writer = os.Stdout
+	if flags.Output != "" {
+		file, err := os.Create(flags.Output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+	switch flags.Format {
+	case "csv":
+		return report.WriteTrendsCSV(writer, trends)
+	case "html":
+		return report.WriteTrendsHTML(writer, trends, now.Add(-window), now)
+	}
+	return report.WriteTrendsTable(writer, trends)
+}
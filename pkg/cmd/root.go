@@ -18,7 +18,15 @@ package cmd
 import (
 	"fmt"
 	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/cmd/apply"
+	"jenkinsctl/pkg/cmd/audit"
+	"jenkinsctl/pkg/cmd/backup"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"jenkinsctl/pkg/cmd/exporter"
 	"jenkinsctl/pkg/cmd/job"
+	"jenkinsctl/pkg/cmd/report"
+	"jenkinsctl/pkg/cmd/serve"
+	"jenkinsctl/pkg/cmd/ui"
 	"os"
 	"strings"
 
@@ -38,7 +46,6 @@ func Execute(rootCmd *cobra.Command) {
 func NewRootCmd(client *apiclient.ApiClient) *cobra.Command {
 
 	cobra.OnInitialize(initConfig)
-	cobra.OnInitialize(client.Initialize)
 
 	// cmd represents the base command when called without any subcommands
 	var cmd = &cobra.Command{
@@ -46,6 +53,15 @@ func NewRootCmd(client *apiclient.ApiClient) *cobra.Command {
 		Short: "CLI for managing jobs on Jenkins ",
 		Long: `With this command-line tool you will be able to list, start and stop jobs on Jenkins.
 This tool was developed as part of a technical challenge for Bonitasoft.`,
+		// The client is connected before running the commands, the ones which
+		// do not talk to Jenkins replacing this hook.
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			client.Audit = cmdutil.AppendAudit
+			if err := cmdutil.ConnectClient(client); err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				os.Exit(1)
+			}
+		},
 	}
 
 	cmd.PersistentFlags().StringVar(
@@ -53,6 +69,13 @@ This tool was developed as part of a technical challenge for Bonitasoft.`,
 	)
 
 	cmd.AddCommand(job.NewJobCmd(client))
+	cmd.AddCommand(backup.NewBackupCmd(client))
+	cmd.AddCommand(apply.NewApplyCmd(client))
+	cmd.AddCommand(ui.NewUiCmd(client))
+	cmd.AddCommand(report.NewReportCmd(client))
+	cmd.AddCommand(exporter.NewExporterCmd(client))
+	cmd.AddCommand(serve.NewServeCmd(client))
+	cmd.AddCommand(audit.NewAuditCmd(client))
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
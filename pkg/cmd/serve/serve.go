@@ -0,0 +1,115 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/server"
+	"jenkinsctl/pkg/audit"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+type ServeFlags struct {
+	Listen    string
+	TokenFile string
+	AuditLog  string
+	OpenAPI   bool
+}
+
+func newServeFlags() *ServeFlags {
+	return &ServeFlags{
+		Listen:    "127.0.0.1:8080",
+		TokenFile: "",
+		AuditLog:  "",
+		OpenAPI:   false,
+	}
+}
+
+func NewServeCmd(client *apiclient.ApiClient) *cobra.Command {
+	serveFlags := newServeFlags()
+
+	// cmd represents the serve command
+	var cmd = &cobra.Command{
+		Use:   "serve",
+		Short: "serve the operations on the jobs as a JSON API",
+		Long: `This command runs a HTTP server exposing the listing, start, stop and schedule of jobs
+and the console logs of the builds, streamed as server-sent events, as a JSON API
+
+The clients authenticate with a bearer token read from the token file, which contains one
+"<client> <token>" pair per line, and the actions they request are recorded in the audit log,
+like the ones of the command line, with the name and the address of the client
+The OpenAPI spec of the API is served under /api/v1/openapi.json, or printed with --openapi
+For example:
+	jenkinsctl serve --token-file=tokens.txt
+	jenkinsctl serve --listen=:8080 --token-file=tokens.txt --audit-log=audit.jsonl
+	jenkinsctl serve --openapi > openapi.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(client, serveFlags)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&serveFlags.Listen, "listen", serveFlags.Listen,
+		"Address on which the API is served",
+	)
+	cmd.Flags().StringVar(
+		&serveFlags.TokenFile, "token-file", serveFlags.TokenFile,
+		"File of the tokens of the clients, one \"<client> <token>\" pair per line",
+	)
+	cmd.Flags().StringVar(
+		&serveFlags.AuditLog, "audit-log", serveFlags.AuditLog,
+		"Audit log in which the actions are recorded, instead of the one of the configuration",
+	)
+	cmd.Flags().BoolVar(
+		&serveFlags.OpenAPI, "openapi", serveFlags.OpenAPI,
+		"Print the OpenAPI spec of the API and exit",
+	)
+	return cmd
+}
+
+func serve(client *apiclient.ApiClient, flags *ServeFlags) error {
+	if flags.OpenAPI {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(server.OpenAPISpec())
+	}
+	if flags.TokenFile == "" {
+		return errors.New("--token-file is required")
+	}
+	tokens, err := server.LoadTokens(flags.TokenFile)
+	if err != nil {
+		return err
+	}
+
+	record := client.Audit
+	if flags.AuditLog != "" {
+		record = func(entry *audit.Entry) {
+			cmdutil.AppendAuditFile(flags.AuditLog, entry)
+		}
+	}
+
+	apiServer := server.NewServer(client, tokens, record)
+	fmt.Fprintf(os.Stderr, "Serving the API on %s for %d clients\n", flags.Listen, len(tokens))
+	return http.ListenAndServe(flags.Listen, apiServer.Handler())
+}
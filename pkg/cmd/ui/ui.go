@@ -0,0 +1,70 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"errors"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func NewUiCmd(client *apiclient.ApiClient) *cobra.Command {
+	interval := 5 * time.Second
+	dryRun := jobs.DRY_RUN_NONE
+
+	// cmd represents the ui command
+	var cmd = &cobra.Command{
+		Use:   "ui",
+		Short: "dashboard of the jobs in the terminal",
+		Long: `This command displays a full-screen dashboard of the jobs, refreshed every interval
+
+The jobs are selected with the arrows, typing / filters them from their name
+and the following keys act on the selected job:
+	s	start the job
+	x	stop the job
+	l	show the logs of the last build
+	h	show the build history
+	c	show the configuration
+	r	refresh the jobs
+	q	quit
+
+For example:
+	jenkinsctl ui
+	jenkinsctl ui --interval=10s
+	jenkinsctl ui --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				return errors.New("--interval must be positive")
+			}
+			return Run(client, interval, dryRun)
+		},
+	}
+
+	cmd.Flags().DurationVar(
+		&interval, "interval", interval,
+		"Interval between two refreshes of the jobs",
+	)
+	cmdutil.AddDryRunFlag(
+		cmd, &dryRun,
+		"Only show what the start and stop keys would do without changing the jobs",
+	)
+	return cmd
+}
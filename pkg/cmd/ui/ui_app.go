@@ -0,0 +1,439 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/cmd/cmdutil"
+	"jenkinsctl/pkg/jenkinsctl"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const historySize = 20
+
+var statusColors = map[string]string{
+	jobs.JOB_STATUS_RUNNING:  "\033[36m",
+	jobs.JOB_STATUS_SUCCESS:  "\033[32m",
+	jobs.JOB_STATUS_UNSTABLE: "\033[33m",
+	jobs.JOB_STATUS_FAILED:   "\033[31m",
+	jobs.JOB_STATUS_ABORTED:  "\033[90m",
+	jobs.JOB_STATUS_DISABLED: "\033[90m",
+}
+
+type pollResult struct {
+	jobs jobs.Jobs
+	err  error
+	date time.Time
+}
+
+// textView is a scrollable text displayed over the jobs list, for the logs,
+// the build history and the configuration of a job.
+type textView struct {
+	title  string
+	lines  []string
+	offset int
+}
+
+type app struct {
+	client   *apiclient.ApiClient
+	library  *jenkinsctl.Client
+	interval time.Duration
+	dryRun   string
+	fd       int
+	out      *bufio.Writer
+	restore  func() error
+	width    int
+	height   int
+
+	jobs       []jobs.Job
+	visible    []int
+	selected   int
+	offset     int
+	filter     string
+	filtering  bool
+	message    string
+	lastUpdate time.Time
+	pollErr    error
+	text       *textView
+	quit       bool
+
+	results chan pollResult
+	refresh chan struct{}
+}
+
+// Run displays the dashboard until the user quits, the actions on the jobs
+// being only planned with a dry run mode other than none. The terminal is
+// restored even when drawing panics, the panic being returned as an error.
+func Run(client *apiclient.ApiClient, interval time.Duration, dryRun string) (err error) {
+	if !cmdutil.IsTerminal(os.Stdin) || !cmdutil.IsTerminal(os.Stdout) {
+		return fmt.Errorf("the ui command must be run in a terminal")
+	}
+	library, err := cmdutil.NewLibraryClient(client, dryRun)
+	if err != nil {
+		return err
+	}
+	a := &app{
+		client:   client,
+		library:  library,
+		interval: interval,
+		dryRun:   dryRun,
+		fd:       int(os.Stdin.Fd()),
+		out:      bufio.NewWriter(os.Stdout),
+		message:  "loading jobs...",
+		results:  make(chan pollResult, 1),
+		refresh:  make(chan struct{}, 1),
+	}
+	if err := a.start(); err != nil {
+		return err
+	}
+	defer func() {
+		a.stop()
+		if r := recover(); r != nil {
+			err = fmt.Errorf("ui: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	go a.poll()
+	buf := make([]byte, 64)
+	for !a.quit {
+		select {
+		case result := <-a.results:
+			a.update(result)
+		default:
+		}
+		a.draw()
+
+		n, err := readInput(a.fd, buf)
+		if err != nil {
+			return err
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			a.handleKey(k)
+		}
+	}
+	return nil
+}
+
+// start switches to the alternate screen in raw mode, stop restores the
+// terminal as it was.
+func (a *app) start() error {
+	restore, err := makeRaw(a.fd)
+	if err != nil {
+		return err
+	}
+	a.restore = restore
+	a.out.WriteString("\033[?1049h\033[?25l")
+	return a.out.Flush()
+}
+
+func (a *app) stop() {
+	a.out.WriteString("\033[?25h\033[?1049l")
+	a.out.Flush()
+	a.restore()
+}
+
+// poll lists the jobs every interval, or when a refresh is asked.
+func (a *app) poll() {
+	for {
+		var listed jobs.Jobs
+		err := listed.GetFilteredJobs(a.client, &jobs.JobsFilterParams{Status: jobs.JOB_STATUS_ALL})
+		a.results <- pollResult{jobs: listed, err: err, date: time.Now()}
+		select {
+		case <-time.After(a.interval):
+		case <-a.refresh:
+		}
+	}
+}
+
+func (a *app) askRefresh() {
+	select {
+	case a.refresh <- struct{}{}:
+	default:
+	}
+}
+
+func (a *app) update(result pollResult) {
+	a.pollErr = result.err
+	if result.err != nil {
+		return
+	}
+	selectedName := ""
+	if job := a.selectedJob(); job != nil {
+		selectedName = job.Name
+	}
+	a.jobs = result.jobs.Jobs
+	a.lastUpdate = result.date
+	if a.message == "loading jobs..." {
+		a.message = ""
+	}
+	a.applyFilter()
+	for i, index := range a.visible {
+		if a.jobs[index].Name == selectedName {
+			a.selected = i
+		}
+	}
+}
+
+// applyFilter keeps the jobs whose name contains the filter, ignoring case.
+func (a *app) applyFilter() {
+	a.visible = a.visible[:0]
+	filter := strings.ToLower(a.filter)
+	for i := range a.jobs {
+		if strings.Contains(strings.ToLower(a.jobs[i].Name), filter) {
+			a.visible = append(a.visible, i)
+		}
+	}
+	if a.selected >= len(a.visible) {
+		a.selected = len(a.visible) - 1
+	}
+	if a.selected < 0 {
+		a.selected = 0
+	}
+}
+
+func (a *app) selectedJob() *jobs.Job {
+	if a.selected < 0 || a.selected >= len(a.visible) {
+		return nil
+	}
+	return &a.jobs[a.visible[a.selected]]
+}
+
+func (a *app) listHeight() int {
+	return a.height - 4
+}
+
+func (a *app) handleKey(k key) {
+	if k.code == keyInterrupt {
+		a.quit = true
+		return
+	}
+	if a.text != nil {
+		a.handleTextKey(k)
+		return
+	}
+
+	switch {
+	case k.code == keyUp || (!a.filtering && k.r == 'k'):
+		a.selected--
+	case k.code == keyDown || (!a.filtering && k.r == 'j'):
+		a.selected++
+	case k.code == keyPageUp:
+		a.selected -= a.listHeight()
+	case k.code == keyPageDown:
+		a.selected += a.listHeight()
+	case k.code == keyHome:
+		a.selected = 0
+	case k.code == keyEnd:
+		a.selected = len(a.visible) - 1
+	case a.filtering:
+		a.handleFilterKey(k)
+	case k.code != keyRune:
+	case k.r == 'q':
+		a.quit = true
+	case k.r == '/':
+		a.filtering = true
+	case k.r == 'r':
+		a.message = "refreshing..."
+		a.askRefresh()
+	case k.r == 's':
+		a.runAction("start")
+	case k.r == 'x':
+		a.runAction("stop")
+	case k.r == 'l':
+		a.showLogs()
+	case k.r == 'h':
+		a.showHistory()
+	case k.r == 'c':
+		a.showConfig()
+	}
+	if a.selected >= len(a.visible) {
+		a.selected = len(a.visible) - 1
+	}
+	if a.selected < 0 {
+		a.selected = 0
+	}
+}
+
+func (a *app) handleFilterKey(k key) {
+	switch k.code {
+	case keyRune:
+		a.filter += string(k.r)
+	case keyBackspace:
+		if runes := []rune(a.filter); len(runes) > 0 {
+			a.filter = string(runes[:len(runes)-1])
+		}
+	case keyEnter:
+		a.filtering = false
+	case keyEscape:
+		a.filter = ""
+		a.filtering = false
+	}
+	a.applyFilter()
+}
+
+func (a *app) handleTextKey(k key) {
+	page := a.height - 3
+	switch {
+	case k.code == keyUp || k.r == 'k':
+		a.text.offset--
+	case k.code == keyDown || k.r == 'j':
+		a.text.offset++
+	case k.code == keyPageUp:
+		a.text.offset -= page
+	case k.code == keyPageDown || k.r == ' ':
+		a.text.offset += page
+	case k.code == keyHome || k.r == 'g':
+		a.text.offset = 0
+	case k.code == keyEnd || k.r == 'G':
+		a.text.offset = len(a.text.lines) - page
+	case k.code == keyEscape || k.r == 'q':
+		a.text = nil
+		return
+	}
+	if a.text.offset > len(a.text.lines)-page {
+		a.text.offset = len(a.text.lines) - page
+	}
+	if a.text.offset < 0 {
+		a.text.offset = 0
+	}
+}
+
+// runAction starts or stops the selected job outside of the dashboard, with
+// the same confirmation as the job start and job stop commands.
+func (a *app) runAction(action string) {
+	job := a.selectedJob()
+	if job == nil {
+		return
+	}
+	a.stop()
+	selection := []jobs.JobSummary{job.Summary()}
+	fmt.Printf("\nJobs to be %s :\n", map[string]string{"start": "started", "stop": "stopped"}[action])
+	jobs.PrintSummariesTable(selection, jobs.DefaultJobColumns, nil)
+	var err error
+	if a.dryRun == jobs.DRY_RUN_NONE {
+		err = cmdutil.AskUserForYesOrNo(action)
+	}
+	if err == nil {
+		filter := jenkinsctl.Filter{Names: cmdutil.ExactNames(selection)}
+		var results []jobs.ActionResult
+		if action == "start" {
+			cmdutil.PrintActionHeader("Starting", a.dryRun)
+			results, err = a.library.Jobs().Start(context.Background(), filter)
+		} else {
+			cmdutil.PrintActionHeader("Stopping", a.dryRun)
+			results, err = a.library.Jobs().Stop(context.Background(), filter)
+		}
+		if a.dryRun != jobs.DRY_RUN_NONE {
+			err = cmdutil.CheckDryRunResults(results, err)
+		}
+	}
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
+	fmt.Print("\nPress Enter to go back to the dashboard")
+	bufio.NewReader(os.Stdin).ReadString('\n')
+
+	if err := a.start(); err != nil {
+		a.quit = true
+		return
+	}
+	a.message = ""
+	a.askRefresh()
+}
+
+// showText displays the text of the selected job returned by load, the
+// dashboard being drawn with a loading message while it is fetched.
+func (a *app) showText(title string, load func(job *jobs.Job) (string, error)) {
+	job := a.selectedJob()
+	if job == nil {
+		return
+	}
+	a.message = fmt.Sprintf("loading %s of %s...", title, job.Name)
+	a.draw()
+	text, err := load(job)
+	if err != nil {
+		a.message = fmt.Sprintf("unable to load %s of %s: %s", title, job.Name, err)
+		return
+	}
+	a.message = ""
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r", ""), "\t", "    ")
+	a.text = &textView{
+		title: fmt.Sprintf("%s of %s", title, job.Name),
+		lines: strings.Split(strings.TrimRight(text, "\n"), "\n"),
+	}
+}
+
+func (a *app) showLogs() {
+	a.showText("logs", func(job *jobs.Job) (string, error) {
+		if job.JenkinsLastBuild == nil || job.JenkinsLastBuild.Raw == nil {
+			return "", fmt.Errorf("the job has no build")
+		}
+		var text strings.Builder
+		err := a.library.Jobs().Logs(
+			context.Background(), job.Name, strconv.FormatInt(job.JenkinsLastBuild.GetBuildNumber(), 10), false,
+			func(chunk string) error {
+				text.WriteString(chunk)
+				return nil
+			},
+		)
+		return text.String(), err
+	})
+}
+
+func (a *app) showHistory() {
+	a.showText("history", func(job *jobs.Job) (string, error) {
+		builds, err := job.BuildHistory(a.client, historySize)
+		if err != nil {
+			return "", err
+		}
+		var text strings.Builder
+		fmt.Fprintf(&text, "%-8s %-10s %-19s %10s\n", "BUILD", "RESULT", "DATE", "DURATION")
+		for _, build := range builds {
+			result := strings.ToLower(build.GetResult())
+			duration := time.Duration(build.GetDuration()) * time.Millisecond
+			if build.Raw.Building {
+				result = jobs.JOB_STATUS_RUNNING
+				duration = time.Since(build.GetTimestamp())
+			}
+			fmt.Fprintf(
+				&text, "%-8s %-10s %-19s %10s\n",
+				fmt.Sprintf("#%d", build.GetBuildNumber()), result,
+				build.GetTimestamp().Format("2006-01-02 15:04:05"),
+				duration.Round(time.Second),
+			)
+		}
+		return text.String(), nil
+	})
+}
+
+func (a *app) showConfig() {
+	a.showText("config", func(job *jobs.Job) (string, error) {
+		config, err := jobs.GetJobConfig(a.client, job.Name)
+		if err != nil {
+			return "", err
+		}
+		return jobs.CanonicalizeConfig(config, false)
+	})
+}
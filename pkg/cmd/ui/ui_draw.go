@@ -0,0 +1,201 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	styleReset   = "\033[0m"
+	styleReverse = "\033[7m"
+	styleBold    = "\033[1m"
+)
+
+// fit truncates or pads the text to the width.
+func fit(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if length := utf8.RuneCountInString(text); length <= width {
+		return text + strings.Repeat(" ", width-length)
+	}
+	runes := []rune(text)
+	if width == 1 {
+		return string(runes[:1])
+	}
+	return string(runes[:width-1]) + "~"
+}
+
+// cell is a column of a row of the jobs list, style being applied to its text.
+type cell struct {
+	text  string
+	width int
+	right bool
+	style string
+}
+
+// renderRow renders the cells, each preceded by a space, on width columns.
+// The cells are fitted one by one so that a style never cuts a character and
+// the last cells are truncated on a narrow terminal.
+func renderRow(cells []cell, width int) string {
+	var line strings.Builder
+	remaining := width
+	for _, c := range cells {
+		if remaining < 2 {
+			break
+		}
+		text := c.text
+		if length := utf8.RuneCountInString(text); c.right && length < c.width {
+			text = strings.Repeat(" ", c.width-length) + text
+		}
+		cellWidth := c.width
+		if cellWidth > remaining-1 {
+			cellWidth = remaining - 1
+		}
+		line.WriteString(" ")
+		if c.style != "" {
+			line.WriteString(c.style + fit(text, cellWidth) + styleReset)
+		} else {
+			line.WriteString(fit(text, cellWidth))
+		}
+		remaining -= cellWidth + 1
+	}
+	line.WriteString(strings.Repeat(" ", remaining))
+	return line.String()
+}
+
+// draw renders the whole screen, the dashboard or the text view.
+func (a *app) draw() {
+	width, height, err := terminalSize(a.fd)
+	if err != nil || width == 0 || height == 0 {
+		width, height = 80, 24
+	}
+	a.width, a.height = width, height
+
+	lines := []string{}
+	if a.text != nil {
+		lines = a.textLines()
+	} else {
+		lines = a.listLines()
+	}
+
+	a.out.WriteString("\033[H")
+	for i, line := range lines {
+		if i > 0 {
+			a.out.WriteString("\r\n")
+		}
+		a.out.WriteString(line)
+		a.out.WriteString(styleReset + "\033[K")
+	}
+	a.out.WriteString("\033[J")
+	a.out.Flush()
+}
+
+func (a *app) titleLine(title string) string {
+	updated := ""
+	if !a.lastUpdate.IsZero() {
+		updated = "updated at " + a.lastUpdate.Format("15:04:05")
+	}
+	left := fmt.Sprintf(" jenkinsctl ui - %s - %s", a.client.Jenkins.Server, title)
+	if a.dryRun != jobs.DRY_RUN_NONE {
+		left += " - dry run"
+	}
+	return styleReverse + fit(left, a.width-len(updated)-1) + updated + " "
+}
+
+func (a *app) listLines() []string {
+	nameWidth := a.width - 10 - 19 - 10 - 4
+	if nameWidth < 10 {
+		nameWidth = 10
+	}
+	row := func(name, status, date, duration string, statusStyle string) string {
+		return renderRow([]cell{
+			{text: name, width: nameWidth},
+			{text: status, width: 10, style: statusStyle},
+			{text: date, width: 19},
+			{text: duration, width: 10, right: true},
+		}, a.width)
+	}
+
+	lines := []string{
+		a.titleLine(fmt.Sprintf("%d/%d jobs", len(a.visible), len(a.jobs))),
+		styleBold + row("NAME", "STATUS", "BUILD DATE", "DURATION", ""),
+	}
+
+	height := a.listHeight()
+	if a.selected < a.offset {
+		a.offset = a.selected
+	}
+	if a.selected >= a.offset+height {
+		a.offset = a.selected - height + 1
+	}
+	for i := a.offset; i < a.offset+height; i++ {
+		if i >= len(a.visible) {
+			lines = append(lines, "")
+			continue
+		}
+		job := &a.jobs[a.visible[i]]
+		status := job.ColumnValue("status")
+		if i == a.selected {
+			lines = append(lines, styleReverse+row(
+				job.Name, status, job.ColumnValue("date"), job.ColumnValue("duration"), "",
+			))
+			continue
+		}
+		lines = append(lines, row(
+			job.Name, status, job.ColumnValue("date"), job.ColumnValue("duration"), statusColors[status],
+		))
+	}
+
+	var message string
+	switch {
+	case a.filtering:
+		message = "filter: " + a.filter + "_"
+	case a.message != "":
+		message = a.message
+	case a.pollErr != nil:
+		message = "error: " + a.pollErr.Error()
+	case a.filter != "":
+		message = "filter: " + a.filter
+	}
+	lines = append(lines, fit(" "+message, a.width))
+	lines = append(lines, styleReverse+fit(
+		" up/down move  / filter  s start  x stop  l logs  h history  c config  r refresh  q quit",
+		a.width,
+	))
+	return lines
+}
+
+func (a *app) textLines() []string {
+	lines := []string{a.titleLine(a.text.title)}
+	height := a.height - 2
+	for i := a.text.offset; i < a.text.offset+height; i++ {
+		if i < len(a.text.lines) {
+			lines = append(lines, fit(a.text.lines[i], a.width))
+		} else {
+			lines = append(lines, "")
+		}
+	}
+	return append(lines, styleReverse+fit(
+		fmt.Sprintf(" up/down scroll  pgup/pgdn page  g/G top/bottom  q back  (%d lines)", len(a.text.lines)),
+		a.width,
+	))
+}
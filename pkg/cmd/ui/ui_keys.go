@@ -0,0 +1,105 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import "unicode/utf8"
+
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyEnter
+	keyEscape
+	keyBackspace
+	keyInterrupt
+	keyUnknown
+)
+
+type key struct {
+	code keyCode
+	r    rune
+}
+
+// escapeSequences maps the escape sequences sent by the terminals, without
+// the leading escape character, to the keys.
+var escapeSequences = map[string]keyCode{
+	"[A":  keyUp,
+	"[B":  keyDown,
+	"OA":  keyUp,
+	"OB":  keyDown,
+	"[5~": keyPageUp,
+	"[6~": keyPageDown,
+	"[H":  keyHome,
+	"[F":  keyEnd,
+	"OH":  keyHome,
+	"OF":  keyEnd,
+	"[1~": keyHome,
+	"[4~": keyEnd,
+	"[7~": keyHome,
+	"[8~": keyEnd,
+}
+
+// parseKeys splits the input read from the terminal in keys.
+func parseKeys(input []byte) []key {
+	keys := []key{}
+	for len(input) > 0 {
+		switch b := input[0]; {
+		case b == 0x1b && len(input) == 1:
+			keys = append(keys, key{code: keyEscape})
+			input = input[1:]
+		case b == 0x1b:
+			// The sequence ends with a letter or a tilde.
+			end := 1
+			for end < len(input) && end < 8 {
+				c := input[end]
+				end++
+				if end > 2 && (c == '~' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')) {
+					break
+				}
+			}
+			code, ok := escapeSequences[string(input[1:end])]
+			if !ok {
+				code = keyUnknown
+			}
+			keys = append(keys, key{code: code})
+			input = input[end:]
+		case b == '\r' || b == '\n':
+			keys = append(keys, key{code: keyEnter})
+			input = input[1:]
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, key{code: keyBackspace})
+			input = input[1:]
+		case b == 0x03:
+			keys = append(keys, key{code: keyInterrupt})
+			input = input[1:]
+		case b < 0x20:
+			keys = append(keys, key{code: keyUnknown})
+			input = input[1:]
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, key{code: keyRune, r: r})
+			input = input[size:]
+		}
+	}
+	return keys
+}
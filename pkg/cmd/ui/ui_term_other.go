@@ -0,0 +1,36 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import "errors"
+
+var errUnsupported = errors.New("the ui command is not supported on this platform")
+
+func makeRaw(fd int) (func() error, error) {
+	return nil, errUnsupported
+}
+
+func terminalSize(fd int) (int, int, error) {
+	return 0, 0, errUnsupported
+}
+
+func readInput(fd int, buf []byte) (int, error) {
+	return 0, errUnsupported
+}
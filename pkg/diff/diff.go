@@ -0,0 +1,243 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	text string
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// computeOps returns the shortest edit script turning a into b, the deleted
+// lines of each change being put before the inserted ones like diff does.
+func computeOps(a, b []string) []op {
+	ops := appendOps(make([]op, 0, len(a)+len(b)), a, b)
+	for start := 0; start < len(ops); {
+		if ops[start].kind == opEqual {
+			start++
+			continue
+		}
+		end := start
+		for end < len(ops) && ops[end].kind != opEqual {
+			end++
+		}
+		sort.SliceStable(ops[start:end], func(i, j int) bool {
+			return ops[start+i].kind == opDelete && ops[start+j].kind == opInsert
+		})
+		start = end
+	}
+	return ops
+}
+
+// appendOps appends the shortest edit script turning a into b to ops, with
+// the linear space variant of the Myers algorithm: after setting aside the
+// common first and last lines, the middle snake of the script is found by
+// searching from both ends, and the scripts before and after it are computed
+// recursively. The memory is linear in the number of lines, and the time is
+// proportional to the number of lines times the number of differences.
+func appendOps(ops []op, a, b []string) []op {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		ops = append(ops, op{kind: opEqual, text: a[0]})
+		a, b = a[1:], b[1:]
+	}
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			ops = append(ops, op{kind: opInsert, text: line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			ops = append(ops, op{kind: opDelete, text: line})
+		}
+	default:
+		x, y, u, v := middleSnake(a, b)
+		ops = appendOps(ops, a[:x], b[:y])
+		for _, line := range a[x:u] {
+			ops = append(ops, op{kind: opEqual, text: line})
+		}
+		ops = appendOps(ops, a[u:], b[v:])
+	}
+
+	for _, line := range common {
+		ops = append(ops, op{kind: opEqual, text: line})
+	}
+	return ops
+}
+
+// middleSnake returns the snake from (x, y) to (u, v) in the middle of a
+// shortest edit script turning a into b, a and b being neither empty nor
+// starting or ending with the same line.
+//
+// The forward search follows the diagonals k = x - y from (0, 0), and the
+// backward search the diagonals of the reversed texts from (len(a), len(b)),
+// forward[k] and backward[k] holding the furthest x reached on each diagonal.
+// The backward diagonal k is the forward diagonal delta - k.
+func middleSnake(a, b []string) (int, int, int, int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	forward := make([]int, 2*maxD+3)
+	backward := make([]int, 2*maxD+3)
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			if delta%2 != 0 && delta-k >= -(d-1) && delta-k <= d-1 &&
+				x+backward[offset+delta-k] >= n {
+				return x0, y0, x, y
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			if delta%2 == 0 && delta-k >= -d && delta-k <= d &&
+				x+forward[offset+delta-k] >= n {
+				return n - x, m - y, n - x0, m - y0
+			}
+		}
+	}
+	// Not reached: the searches meet after at most (n + m + 1) / 2 steps.
+	return 0, 0, 0, 0
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// Unified returns the differences between the a and b texts in unified diff
+// format with the given number of context lines, or an empty string when both
+// texts are identical.
+func Unified(fromName, toName, a, b string, context int) string {
+	ops := computeOps(splitLines(a), splitLines(b))
+
+	changes := []int{}
+	for i, o := range ops {
+		if o.kind != opEqual {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	// aPos and bPos hold the line number (starting at 1) of each operation
+	// in the a and b texts.
+	aPos := make([]int, len(ops)+1)
+	bPos := make([]int, len(ops)+1)
+	aPos[0], bPos[0] = 1, 1
+	for i, o := range ops {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if o.kind != opInsert {
+			aPos[i+1]++
+		}
+		if o.kind != opDelete {
+			bPos[i+1]++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	for first := 0; first < len(changes); {
+		last := first
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*context+1 {
+			last++
+		}
+		start := changes[first] - context
+		if start < 0 {
+			start = 0
+		}
+		end := changes[last] + context + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		fmt.Fprintf(
+			&out, "@@ -%s +%s @@\n",
+			hunkRange(aPos[start], aPos[end]-aPos[start]),
+			hunkRange(bPos[start], bPos[end]-bPos[start]),
+		)
+		for _, o := range ops[start:end] {
+			switch o.kind {
+			case opEqual:
+				out.WriteString(" " + o.text + "\n")
+			case opDelete:
+				out.WriteString("-" + o.text + "\n")
+			case opInsert:
+				out.WriteString("+" + o.text + "\n")
+			}
+		}
+		first = last + 1
+	}
+	return out.String()
+}
@@ -0,0 +1,167 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// editDistance returns the number of lines deleted and inserted by a shortest
+// edit script turning a into b, from the longest common subsequence.
+func editDistance(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] > lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	return len(a) + len(b) - 2*lcs[0][0]
+}
+
+// checkOps checks that the script turns a into b with the fewest changes,
+// the deleted lines of each change coming first.
+func checkOps(t *testing.T, a, b []string, ops []op) {
+	t.Helper()
+	from, to, changes := []string{}, []string{}, 0
+	for i, o := range ops {
+		if i > 0 && o.kind == opDelete && ops[i-1].kind == opInsert {
+			t.Errorf("computeOps(%q, %q) inserts before deleting: %v", a, b, ops)
+		}
+		if o.kind != opInsert {
+			from = append(from, o.text)
+		}
+		if o.kind != opDelete {
+			to = append(to, o.text)
+		}
+		if o.kind != opEqual {
+			changes++
+		}
+	}
+	if strings.Join(from, "\n") != strings.Join(a, "\n") || strings.Join(to, "\n") != strings.Join(b, "\n") {
+		t.Errorf("computeOps(%q, %q) does not turn a into b: %v", a, b, ops)
+	}
+	if want := editDistance(a, b); changes != want {
+		t.Errorf("computeOps(%q, %q) has %d changes, want %d", a, b, changes, want)
+	}
+}
+
+func TestComputeOps(t *testing.T) {
+	tests := []struct {
+		a string
+		b string
+	}{
+		{"", ""},
+		{"", "a\nb"},
+		{"a\nb", ""},
+		{"a\nb\nc", "a\nb\nc"},
+		{"a\nb\nc", "x\ny"},
+		{"a\nb\nc", "a\nx\nc"},
+		{"a\nb\nc\na\nb\nb\na", "c\nb\na\nb\na\nc"},
+		{"a\nx\nb\ny\nc", "x\na\ny\nb\nz\nc"},
+		{"a\na\na\nb", "b\na\na\na"},
+	}
+	for _, test := range tests {
+		a, b := splitLines(test.a), splitLines(test.b)
+		checkOps(t, a, b, computeOps(a, b))
+	}
+}
+
+func TestComputeOpsRandom(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	lines := func() []string {
+		result := make([]string, random.Intn(12))
+		for i := range result {
+			result[i] = string(rune('a' + random.Intn(3)))
+		}
+		return result
+	}
+	for i := 0; i < 2000; i++ {
+		a, b := lines(), lines()
+		checkOps(t, a, b, computeOps(a, b))
+	}
+}
+
+func TestMiddleSnake(t *testing.T) {
+	tests := []struct {
+		a string
+		b string
+	}{
+		{"a", "b"},
+		{"a\nb\nc\na\nb\nb\na", "c\nb\na\nb\na\nc"},
+		{"x\na\nb\nc\ny", "z\na\nb\nc\nw"},
+		{"a\nb\nc\nd\ne", "f\ng"},
+		{"a\nb", "b\na\nb\nc"},
+	}
+	for _, test := range tests {
+		a, b := splitLines(test.a), splitLines(test.b)
+		x, y, u, v := middleSnake(a, b)
+		if x < 0 || y < 0 || u < x || v < y || u > len(a) || v > len(b) || u-x != v-y {
+			t.Errorf("middleSnake(%q, %q) = %d, %d, %d, %d, not a snake", a, b, x, y, u, v)
+			continue
+		}
+		for i := 0; i < u-x; i++ {
+			if a[x+i] != b[y+i] {
+				t.Errorf("middleSnake(%q, %q) = %d, %d, %d, %d, lines differ", a, b, x, y, u, v)
+				break
+			}
+		}
+		// The snake splits a shortest script into two shortest scripts.
+		if editDistance(a[:x], b[:y])+editDistance(a[u:], b[v:]) != editDistance(a, b) {
+			t.Errorf("middleSnake(%q, %q) = %d, %d, %d, %d, not on a shortest script", a, b, x, y, u, v)
+		}
+	}
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{"identical", "a\nb\n", "a\nb\n", ""},
+		{"both empty", "", "", ""},
+		{"empty a", "", "a\nb\n", "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"empty b", "a\nb\n", "", "--- a\n+++ b\n@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{"full replacement", "a\nb\n", "c\n", "--- a\n+++ b\n@@ -1,2 +1 @@\n-a\n-b\n+c\n"},
+		{
+			"context", "1\n2\n3\n4\n5\n6\n7\n8\n9\n", "1\n2\n3\n4\nx\n6\n7\n8\n9\n",
+			"--- a\n+++ b\n@@ -3,5 +3,5 @@\n 3\n 4\n-5\n+x\n 6\n 7\n",
+		},
+		{
+			"separate hunks", "1\n2\n3\n4\n5\n6\n7\n8\n", "x\n2\n3\n4\n5\n6\n7\ny\n",
+			"--- a\n+++ b\n@@ -1,3 +1,3 @@\n-1\n+x\n 2\n 3\n@@ -6,3 +6,3 @@\n 6\n 7\n-8\n+y\n",
+		},
+	}
+	for _, test := range tests {
+		if got := Unified("a", "b", test.a, test.b, 2); got != test.want {
+			t.Errorf("%s: Unified() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
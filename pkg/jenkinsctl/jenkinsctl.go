@@ -0,0 +1,147 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jenkinsctl is the Go library of jenkinsctl: it lists, starts, stops
+// and schedules the jobs of a Jenkins server and returns structured results
+// instead of printing them.
+//
+//	client, err := jenkinsctl.New(jenkinsctl.Options{
+//		Address:  "https://jenkins.example.com",
+//		Username: "admin",
+//		Token:    os.Getenv("JENKINS_TOKEN"),
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	failed, err := client.Jobs().List(ctx, jenkinsctl.Filter{Status: "failure"})
+package jenkinsctl
+
+import (
+	"context"
+	"errors"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/audit"
+	"net/http"
+	"time"
+)
+
+// DefaultMaxConcurrentRequests is the number of requests sent at the same
+// time to Jenkins when Options.MaxConcurrentRequests is not set, like the
+// default of the command line.
+const DefaultMaxConcurrentRequests = 3
+
+// Options configures the client of a Jenkins server.
+type Options struct {
+	Address  string
+	Username string
+	Token    string
+	// MaxConcurrentRequests limits the requests sent at the same time to
+	// Jenkins when listing the jobs.
+	MaxConcurrentRequests int
+	// HTTPClient is used for the requests to Jenkins, http.DefaultClient
+	// when nil.
+	HTTPClient *http.Client
+	// OnEvent, when set, is called with the outcome of an action on each job
+	// as soon as it is known.
+	OnEvent func(event Event)
+	// DryRun is one of DryRunNone (the default), DryRunClient and
+	// DryRunServer. On a dry run the actions return the planned results
+	// without changing the jobs, DryRunServer also validating them against
+	// Jenkins where it allows it.
+	DryRun string
+	// Audit, when set, is called with each action run on the jobs, the dry
+	// runs excepted, like the audit log of the command line.
+	Audit func(entry *audit.Entry)
+}
+
+// Event is the outcome of an action on a job.
+type Event struct {
+	Time   time.Time
+	Action string
+	Result ActionResult
+}
+
+// The dry run modes of Options.DryRun.
+const (
+	DryRunNone   = jobs.DRY_RUN_NONE
+	DryRunClient = jobs.DRY_RUN_CLIENT
+	DryRunServer = jobs.DRY_RUN_SERVER
+)
+
+// Job is the state of a job and of its last build.
+type Job = jobs.JobSummary
+
+// ActionResult is the outcome of an action on a job.
+type ActionResult = jobs.ActionResult
+
+// Client is a client of a Jenkins server, safe for concurrent use.
+type Client struct {
+	api     *apiclient.ApiClient
+	onEvent func(event Event)
+	dryRun  string
+}
+
+// New returns a client of the Jenkins server of the options, without
+// connecting to it, see Ping.
+func New(opts Options) (*Client, error) {
+	switch {
+	case opts.Address == "":
+		return nil, errors.New("jenkins server address not defined")
+	case opts.Username == "":
+		return nil, errors.New("jenkins server username not defined")
+	case opts.Token == "":
+		return nil, errors.New("jenkins server token not defined")
+	}
+	if opts.MaxConcurrentRequests <= 0 {
+		opts.MaxConcurrentRequests = DefaultMaxConcurrentRequests
+	}
+	if opts.DryRun == "" {
+		opts.DryRun = DryRunNone
+	}
+	if err := jobs.CheckDryRun(opts.DryRun); err != nil {
+		return nil, err
+	}
+	api := apiclient.NewClient(
+		opts.Address, opts.Username, opts.Token, opts.MaxConcurrentRequests, opts.HTTPClient,
+	)
+	api.Audit = opts.Audit
+	return &Client{
+		api:     api,
+		onEvent: opts.OnEvent,
+		dryRun:  opts.DryRun,
+	}, nil
+}
+
+// Ping checks that Jenkins can be reached with the credentials.
+func (client *Client) Ping(ctx context.Context) error {
+	_, err := client.api.Jenkins.Init(ctx)
+	return err
+}
+
+// Jobs returns the operations on the jobs.
+func (client *Client) Jobs() *JobsService {
+	return &JobsService{client: client}
+}
+
+func (client *Client) emit(action string, results []ActionResult) {
+	if client.onEvent == nil {
+		return
+	}
+	for _, result := range results {
+		client.onEvent(Event{Time: time.Now(), Action: action, Result: result})
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jenkinsctl
+
+import (
+	"context"
+	"fmt"
+	"jenkinsctl/pkg/apiclient"
+	"jenkinsctl/pkg/apiclient/jobs"
+	"jenkinsctl/pkg/selector"
+	"time"
+)
+
+const (
+	ACTION_START    = "start"
+	ACTION_STOP     = "stop"
+	ACTION_SCHEDULE = "schedule"
+)
+
+// Filter selects jobs like the selection flags of the command line, an empty
+// filter selecting every job.
+type Filter struct {
+	// Names are glob patterns on the full name of the jobs, like "team/*".
+	Names   []string
+	Regex   string
+	Exclude []string
+	// Status is a comma separated list of statuses, like "failure,unstable"
+	// or "!success".
+	Status string
+	// Selector is an expression like "status in (failure, unstable) && age < 2h".
+	Selector string
+	// MinAge and MaxAge select the jobs from the age of their last build,
+	// Since and Until from its start date.
+	MinAge time.Duration
+	MaxAge time.Duration
+	Since  time.Time
+	Until  time.Time
+}
+
+// JobsService gives the operations on the jobs.
+type JobsService struct {
+	client *Client
+}
+
+// params returns the filter of the jobs package, status replacing the status
+// of the filter when it is not empty.
+func (filter *Filter) params(status string) (*jobs.JobsFilterParams, error) {
+	params := &jobs.JobsFilterParams{
+		Names:   filter.Names,
+		Regex:   filter.Regex,
+		Exclude: filter.Exclude,
+		AgeMin:  filter.MinAge,
+		AgeMax:  filter.MaxAge,
+		Since:   filter.Since,
+		Until:   filter.Until,
+		Status:  filter.Status,
+	}
+	if status != "" {
+		params.Status = status
+	}
+	if params.Status == "" {
+		params.Status = jobs.JOB_STATUS_ALL
+	}
+	if filter.Selector != "" {
+		jobSelector, err := selector.Compile(filter.Selector, jobs.SelectorSchema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %s", err)
+		}
+		params.Selector = jobSelector
+	}
+	return params, nil
+}
+
+func (service *JobsService) list(ctx context.Context, filter Filter, status string) (*apiclient.ApiClient, *jobs.Jobs, error) {
+	params, err := filter.params(status)
+	if err != nil {
+		return nil, nil, err
+	}
+	clt := service.client.api.WithContext(ctx)
+	listed := &jobs.Jobs{}
+	if err := listed.GetFilteredJobs(clt, params); err != nil {
+		return nil, nil, err
+	}
+	return clt, listed, nil
+}
+
+// List returns the jobs matching the filter.
+func (service *JobsService) List(ctx context.Context, filter Filter) ([]Job, error) {
+	_, listed, err := service.list(ctx, filter, "")
+	if err != nil {
+		return nil, err
+	}
+	return listed.Summaries(), nil
+}
+
+// run runs an action on the jobs matching the filter one job at a time, so
+// that an event is emitted for each job as soon as it is done, the action
+// being recorded once in the audit hook. On error, the results of the jobs
+// done before are returned with the error.
+func (service *JobsService) run(
+	ctx context.Context, filter Filter, status string, action string,
+	run func(clt *apiclient.ApiClient, selected *jobs.Jobs) ([]ActionResult, error),
+) (results []ActionResult, err error) {
+	clt, listed, err := service.list(ctx, filter, status)
+	if err != nil {
+		return nil, err
+	}
+	if service.client.dryRun == DryRunNone {
+		defer func() { jobs.RecordAudit(clt, action, listed.Names(), results, err) }()
+	}
+	results = []ActionResult{}
+	for i := range listed.Jobs {
+		selected := &jobs.Jobs{Jobs: listed.Jobs[i : i+1]}
+		jobResults, err := run(clt.WithoutAudit(), selected)
+		results = append(results, jobResults...)
+		service.client.emit(action, jobResults)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// Start starts the jobs matching the filter which are not running, or
+// returns the builds it would start on a dry run.
+func (service *JobsService) Start(ctx context.Context, filter Filter) ([]ActionResult, error) {
+	return service.run(ctx, filter, "", ACTION_START,
+		func(clt *apiclient.ApiClient, selected *jobs.Jobs) ([]ActionResult, error) {
+			return selected.StartJobs(clt, service.client.dryRun)
+		},
+	)
+}
+
+// Stop stops the last build of the jobs matching the filter which are
+// running, the status of the filter being ignored.
+func (service *JobsService) Stop(ctx context.Context, filter Filter) ([]ActionResult, error) {
+	return service.run(ctx, filter, jobs.JOB_STATUS_RUNNING, ACTION_STOP,
+		func(clt *apiclient.ApiClient, selected *jobs.Jobs) ([]ActionResult, error) {
+			return selected.StopJobs(clt, service.client.dryRun)
+		},
+	)
+}
+
+// Schedule sets the schedule, in Jenkins time trigger syntax, of the pipeline
+// jobs matching the filter.
+func (service *JobsService) Schedule(ctx context.Context, filter Filter, schedule string) ([]ActionResult, error) {
+	return service.run(ctx, filter, "", ACTION_SCHEDULE,
+		func(clt *apiclient.ApiClient, selected *jobs.Jobs) ([]ActionResult, error) {
+			return selected.ScheduleJobs(clt, schedule, service.client.dryRun)
+		},
+	)
+}
+
+// Logs calls write with the console log of a build of the job located at
+// fullName, the build being given by its number or by one of "last",
+// "lastSuccessful", "lastStable", "lastCompleted" and "lastFailed". With
+// follow, the log is written as it grows until the build is over or ctx is
+// done.
+func (service *JobsService) Logs(
+	ctx context.Context, fullName string, build string, follow bool, write func(text string) error,
+) error {
+	clt := service.client.api.WithContext(ctx)
+	number, err := jobs.GetBuildNumber(clt, fullName, build)
+	if err != nil {
+		return err
+	}
+	return jobs.FollowBuildLog(ctx, clt, fullName, number, follow, 2*time.Second, write)
+}
@@ -0,0 +1,222 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	JOB_TYPE_PIPELINE  = "pipeline"
+	JOB_TYPE_FREESTYLE = "freestyle"
+
+	PARAMETER_TYPE_STRING  = "string"
+	PARAMETER_TYPE_BOOLEAN = "boolean"
+	PARAMETER_TYPE_CHOICE  = "choice"
+
+	JOB_CLASS_PIPELINE  = "org.jenkinsci.plugins.workflow.job.WorkflowJob"
+	JOB_CLASS_FREESTYLE = "hudson.model.FreeStyleProject"
+)
+
+// IsManifestClass reports whether a Jenkins job of this class can be described
+// by a manifest, the other jobs being left alone by apply.
+func IsManifestClass(class string) bool {
+	return class == JOB_CLASS_PIPELINE || class == JOB_CLASS_FREESTYLE
+}
+
+type File struct {
+	Jobs []Job `yaml:"jobs"`
+}
+
+type Job struct {
+	Name        string      `yaml:"name"`
+	Folder      string      `yaml:"folder"`
+	Type        string      `yaml:"type"`
+	Description string      `yaml:"description"`
+	Disabled    bool        `yaml:"disabled"`
+	Script      string      `yaml:"script"`
+	Scm         *Scm        `yaml:"scm"`
+	Shell       string      `yaml:"shell"`
+	Parameters  []Parameter `yaml:"parameters"`
+	Triggers    Triggers    `yaml:"triggers"`
+	// Source is the manifest file defining the job.
+	Source string `yaml:"-"`
+}
+
+type Scm struct {
+	Url           string `yaml:"url"`
+	Branch        string `yaml:"branch"`
+	CredentialsId string `yaml:"credentialsId"`
+	ScriptPath    string `yaml:"scriptPath"`
+}
+
+type Parameter struct {
+	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"`
+	Description string   `yaml:"description"`
+	Default     string   `yaml:"default"`
+	Choices     []string `yaml:"choices"`
+}
+
+type Triggers struct {
+	Cron    string `yaml:"cron"`
+	PollScm string `yaml:"pollScm"`
+}
+
+// FullName returns the name of the job prefixed with its folder.
+func (job *Job) FullName() string {
+	if job.Folder == "" {
+		return job.Name
+	}
+	return strings.Trim(job.Folder, "/") + "/" + job.Name
+}
+
+func (job *Job) validate() error {
+	if job.Name == "" {
+		return fmt.Errorf("%s: job name not defined", job.Source)
+	}
+	if strings.Contains(job.Name, "/") {
+		return fmt.Errorf("%s: job %s: the name must not contain a folder", job.Source, job.Name)
+	}
+	switch job.Type {
+	case JOB_TYPE_PIPELINE:
+		if (job.Script == "") == (job.Scm == nil) {
+			return fmt.Errorf(
+				"%s: job %s: a pipeline job needs either a script or an scm", job.Source, job.Name,
+			)
+		}
+	case JOB_TYPE_FREESTYLE:
+		if job.Script != "" {
+			return fmt.Errorf(
+				"%s: job %s: a freestyle job has no script, use shell", job.Source, job.Name,
+			)
+		}
+	default:
+		return fmt.Errorf(
+			"%s: job %s: %s is not accepted type (possible values: pipeline, freestyle)",
+			job.Source, job.Name, job.Type,
+		)
+	}
+	if job.Scm != nil && job.Scm.Url == "" {
+		return fmt.Errorf("%s: job %s: scm url not defined", job.Source, job.Name)
+	}
+	for _, parameter := range job.Parameters {
+		switch parameter.Type {
+		case PARAMETER_TYPE_STRING:
+		case PARAMETER_TYPE_BOOLEAN:
+			if _, err := parseBoolDefault(parameter.Default); err != nil {
+				return fmt.Errorf(
+					"%s: job %s: boolean parameter %s has an invalid default %q",
+					job.Source, job.Name, parameter.Name, parameter.Default,
+				)
+			}
+		case PARAMETER_TYPE_CHOICE:
+			if len(parameter.Choices) == 0 {
+				return fmt.Errorf(
+					"%s: job %s: choice parameter %s has no choices",
+					job.Source, job.Name, parameter.Name,
+				)
+			}
+		default:
+			return fmt.Errorf(
+				"%s: job %s: %s is not accepted parameter type (possible values: string, boolean, choice)",
+				job.Source, job.Name, parameter.Type,
+			)
+		}
+	}
+	return nil
+}
+
+// parseBoolDefault returns the default value of a boolean parameter, false
+// when it is not defined.
+func parseBoolDefault(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+func loadFile(path string) ([]Job, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := File{}
+	if err := yaml.UnmarshalStrict(content, &file); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	for i := range file.Jobs {
+		file.Jobs[i].Source = path
+		if file.Jobs[i].Type == "" {
+			file.Jobs[i].Type = JOB_TYPE_PIPELINE
+		}
+	}
+	return file.Jobs, nil
+}
+
+// Load reads the jobs of a manifest file, or of every .yaml and .yml file of
+// a directory, sorted by full name.
+func Load(path string) ([]Job, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{path}
+	if info.IsDir() {
+		paths = []string{}
+		for _, pattern := range []string{"*.yaml", "*.yml"} {
+			matches, err := filepath.Glob(filepath.Join(path, pattern))
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, matches...)
+		}
+	}
+
+	jobs := []Job{}
+	sources := map[string]string{}
+	for _, path := range paths {
+		fileJobs, err := loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range fileJobs {
+			if err := job.validate(); err != nil {
+				return nil, err
+			}
+			if source, ok := sources[job.FullName()]; ok {
+				return nil, fmt.Errorf(
+					"job %s is defined in %s and %s", job.FullName(), source, job.Source,
+				)
+			}
+			sources[job.FullName()] = job.Source
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].FullName() < jobs[j].FullName()
+	})
+	return jobs, nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"strconv"
+
+	"github.com/beevik/etree"
+)
+
+func createTextElement(parent *etree.Element, name string, text string) *etree.Element {
+	element := parent.CreateElement(name)
+	element.SetText(text)
+	return element
+}
+
+func (parameter *Parameter) render(parent *etree.Element) {
+	var definition *etree.Element
+	switch parameter.Type {
+	case PARAMETER_TYPE_STRING:
+		definition = parent.CreateElement("hudson.model.StringParameterDefinition")
+	case PARAMETER_TYPE_BOOLEAN:
+		definition = parent.CreateElement("hudson.model.BooleanParameterDefinition")
+	case PARAMETER_TYPE_CHOICE:
+		definition = parent.CreateElement("hudson.model.ChoiceParameterDefinition")
+	}
+	createTextElement(definition, "name", parameter.Name)
+	createTextElement(definition, "description", parameter.Description)
+
+	switch parameter.Type {
+	case PARAMETER_TYPE_STRING:
+		createTextElement(definition, "defaultValue", parameter.Default)
+		createTextElement(definition, "trim", "false")
+	case PARAMETER_TYPE_BOOLEAN:
+		// the default is validated when the manifest is loaded
+		value, _ := parseBoolDefault(parameter.Default)
+		createTextElement(definition, "defaultValue", strconv.FormatBool(value))
+	case PARAMETER_TYPE_CHOICE:
+		choices := definition.CreateElement("choices")
+		choices.CreateAttr("class", "java.util.Arrays$ArrayList")
+		array := choices.CreateElement("a")
+		array.CreateAttr("class", "string-array")
+		for _, choice := range parameter.Choices {
+			createTextElement(array, "string", choice)
+		}
+	}
+}
+
+func (job *Job) renderParameters(properties *etree.Element) {
+	if len(job.Parameters) == 0 {
+		return
+	}
+	definitions := properties.CreateElement("hudson.model.ParametersDefinitionProperty").
+		CreateElement("parameterDefinitions")
+	for _, parameter := range job.Parameters {
+		parameter.render(definitions)
+	}
+}
+
+func (job *Job) renderTriggers(triggers *etree.Element) {
+	if job.Triggers.Cron != "" {
+		timerTrigger := triggers.CreateElement("hudson.triggers.TimerTrigger")
+		createTextElement(timerTrigger, "spec", job.Triggers.Cron)
+	}
+	if job.Triggers.PollScm != "" {
+		scmTrigger := triggers.CreateElement("hudson.triggers.SCMTrigger")
+		createTextElement(scmTrigger, "spec", job.Triggers.PollScm)
+		createTextElement(scmTrigger, "ignorePostCommitHooks", "false")
+	}
+}
+
+func (scm *Scm) render(parent *etree.Element) {
+	element := parent.CreateElement("scm")
+	element.CreateAttr("class", "hudson.plugins.git.GitSCM")
+	element.CreateAttr("plugin", "git")
+	createTextElement(element, "configVersion", "2")
+	remote := element.CreateElement("userRemoteConfigs").
+		CreateElement("hudson.plugins.git.UserRemoteConfig")
+	createTextElement(remote, "url", scm.Url)
+	if scm.CredentialsId != "" {
+		createTextElement(remote, "credentialsId", scm.CredentialsId)
+	}
+	branch := scm.Branch
+	if branch == "" {
+		branch = "main"
+	}
+	createTextElement(
+		element.CreateElement("branches").CreateElement("hudson.plugins.git.BranchSpec"),
+		"name", "*/"+branch,
+	)
+	createTextElement(element, "doGenerateSubmoduleConfigurations", "false")
+	element.CreateElement("submoduleCfg").CreateAttr("class", "empty-list")
+	element.CreateElement("extensions")
+}
+
+func (job *Job) renderPipeline(doc *etree.Document) {
+	root := doc.CreateElement("flow-definition")
+	root.CreateAttr("plugin", "workflow-job")
+	createTextElement(root, "description", job.Description)
+	createTextElement(root, "keepDependencies", "false")
+
+	properties := root.CreateElement("properties")
+	job.renderParameters(properties)
+	if job.Triggers.Cron != "" || job.Triggers.PollScm != "" {
+		job.renderTriggers(
+			properties.CreateElement(
+				"org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty",
+			).CreateElement("triggers"),
+		)
+	}
+
+	definition := root.CreateElement("definition")
+	definition.CreateAttr("plugin", "workflow-cps")
+	if job.Scm != nil {
+		definition.CreateAttr("class", "org.jenkinsci.plugins.workflow.cps.CpsScmFlowDefinition")
+		job.Scm.render(definition)
+		scriptPath := job.Scm.ScriptPath
+		if scriptPath == "" {
+			scriptPath = "Jenkinsfile"
+		}
+		createTextElement(definition, "scriptPath", scriptPath)
+		createTextElement(definition, "lightweight", "true")
+	} else {
+		definition.CreateAttr("class", "org.jenkinsci.plugins.workflow.cps.CpsFlowDefinition")
+		createTextElement(definition, "script", job.Script)
+		createTextElement(definition, "sandbox", "true")
+	}
+	root.CreateElement("triggers")
+	createTextElement(root, "disabled", strconv.FormatBool(job.Disabled))
+}
+
+func (job *Job) renderFreestyle(doc *etree.Document) {
+	root := doc.CreateElement("project")
+	createTextElement(root, "description", job.Description)
+	createTextElement(root, "keepDependencies", "false")
+	job.renderParameters(root.CreateElement("properties"))
+	if job.Scm != nil {
+		job.Scm.render(root)
+	} else {
+		root.CreateElement("scm").CreateAttr("class", "hudson.scm.NullSCM")
+	}
+	createTextElement(root, "canRoam", "true")
+	createTextElement(root, "disabled", strconv.FormatBool(job.Disabled))
+	createTextElement(root, "blockBuildWhenDownstreamBuilding", "false")
+	createTextElement(root, "blockBuildWhenUpstreamBuilding", "false")
+	job.renderTriggers(root.CreateElement("triggers"))
+	createTextElement(root, "concurrentBuild", "false")
+	builders := root.CreateElement("builders")
+	if job.Shell != "" {
+		createTextElement(builders.CreateElement("hudson.tasks.Shell"), "command", job.Shell)
+	}
+	root.CreateElement("publishers")
+	root.CreateElement("buildWrappers")
+}
+
+// Render generates the config.xml of the job.
+func (job *Job) Render() (string, error) {
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version='1.1' encoding='UTF-8'`)
+	if job.Type == JOB_TYPE_FREESTYLE {
+		job.renderFreestyle(doc)
+	} else {
+		job.renderPipeline(doc)
+	}
+	doc.Indent(2)
+	return doc.WriteToString()
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/beevik/etree"
+)
+
+func renderDocument(t *testing.T, job Job) *etree.Document {
+	config, err := job.Render()
+	if err != nil {
+		t.Fatalf("Render(%s) error = %v", job.Name, err)
+	}
+	// the XML 1.1 header written like Jenkins is not read by encoding/xml
+	header := "<?xml version='1.1' encoding='UTF-8'?>\n"
+	if !strings.HasPrefix(config, header) {
+		t.Fatalf("Render(%s) = %s, want the %s header", job.Name, config, strings.TrimSpace(header))
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(strings.TrimPrefix(config, header)); err != nil {
+		t.Fatalf("Render(%s) = %s, not a valid XML: %v", job.Name, config, err)
+	}
+	return doc
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		job  Job
+		want map[string]string
+	}{
+		{
+			Job{Name: "script", Type: JOB_TYPE_PIPELINE, Description: "built", Script: "node {}"},
+			map[string]string{
+				"flow-definition/description":        "built",
+				"flow-definition/definition/script":  "node {}",
+				"flow-definition/definition/sandbox": "true",
+				"flow-definition/disabled":           "false",
+			},
+		},
+		{
+			Job{Name: "scm", Type: JOB_TYPE_PIPELINE, Disabled: true, Scm: &Scm{
+				Url: "https://git/app.git", CredentialsId: "git",
+			}},
+			map[string]string{
+				"flow-definition/definition/scm/userRemoteConfigs/hudson.plugins.git.UserRemoteConfig/url":           "https://git/app.git",
+				"flow-definition/definition/scm/userRemoteConfigs/hudson.plugins.git.UserRemoteConfig/credentialsId": "git",
+				"flow-definition/definition/scm/branches/hudson.plugins.git.BranchSpec/name":                         "*/main",
+				"flow-definition/definition/scriptPath":                                                              "Jenkinsfile",
+				"flow-definition/disabled":                                                                           "true",
+			},
+		},
+		{
+			Job{Name: "triggered", Type: JOB_TYPE_PIPELINE, Script: "node {}", Triggers: Triggers{
+				Cron: "H 2 * * *", PollScm: "H/15 * * * *",
+			}},
+			map[string]string{
+				"flow-definition/properties/org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty/triggers/hudson.triggers.TimerTrigger/spec": "H 2 * * *",
+				"flow-definition/properties/org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty/triggers/hudson.triggers.SCMTrigger/spec":   "H/15 * * * *",
+			},
+		},
+		{
+			Job{Name: "freestyle", Type: JOB_TYPE_FREESTYLE, Shell: "make", Triggers: Triggers{Cron: "H 3 * * *"}},
+			map[string]string{
+				"project/builders/hudson.tasks.Shell/command":        "make",
+				"project/triggers/hudson.triggers.TimerTrigger/spec": "H 3 * * *",
+				"project/disabled": "false",
+			},
+		},
+		{
+			Job{Name: "parameters", Type: JOB_TYPE_FREESTYLE, Parameters: []Parameter{
+				{Name: "target", Type: PARAMETER_TYPE_STRING, Default: "all"},
+				{Name: "verbose", Type: PARAMETER_TYPE_BOOLEAN},
+				{Name: "env", Type: PARAMETER_TYPE_CHOICE, Choices: []string{"dev", "prod"}},
+			}},
+			map[string]string{
+				"project/properties/hudson.model.ParametersDefinitionProperty/parameterDefinitions/hudson.model.StringParameterDefinition/defaultValue":        "all",
+				"project/properties/hudson.model.ParametersDefinitionProperty/parameterDefinitions/hudson.model.BooleanParameterDefinition/defaultValue":       "false",
+				"project/properties/hudson.model.ParametersDefinitionProperty/parameterDefinitions/hudson.model.ChoiceParameterDefinition/choices/a/string[2]": "prod",
+			},
+		},
+	}
+	for _, test := range tests {
+		doc := renderDocument(t, test.job)
+		for path, want := range test.want {
+			element := doc.FindElement(path)
+			if element == nil {
+				t.Errorf("Render(%s): %s not found", test.job.Name, path)
+				continue
+			}
+			if got := element.Text(); got != want {
+				t.Errorf("Render(%s): %s = %q, want %q", test.job.Name, path, got, want)
+			}
+		}
+	}
+}
+
+func TestRenderWithoutOptionalElements(t *testing.T) {
+	tests := []struct {
+		job  Job
+		path string
+	}{
+		{Job{Name: "script", Type: JOB_TYPE_PIPELINE, Script: "node {}"}, "flow-definition/properties/*"},
+		{Job{Name: "script", Type: JOB_TYPE_PIPELINE, Script: "node {}"}, "flow-definition/definition/scm"},
+		{
+			Job{Name: "scm", Type: JOB_TYPE_PIPELINE, Scm: &Scm{Url: "https://git/app.git"}},
+			"flow-definition/definition/scm/userRemoteConfigs/hudson.plugins.git.UserRemoteConfig/credentialsId",
+		},
+		{Job{Name: "freestyle", Type: JOB_TYPE_FREESTYLE}, "project/builders/*"},
+		{Job{Name: "freestyle", Type: JOB_TYPE_FREESTYLE}, "project/triggers/*"},
+	}
+	for _, test := range tests {
+		if element := renderDocument(t, test.job).FindElement(test.path); element != nil {
+			t.Errorf("Render(%s): %s found, want none", test.job.Name, test.path)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		job     Job
+		wantErr string
+	}{
+		{Job{Name: "app", Type: JOB_TYPE_PIPELINE, Script: "node {}"}, ""},
+		{Job{Name: "app", Type: JOB_TYPE_PIPELINE, Scm: &Scm{Url: "https://git/app.git"}}, ""},
+		{Job{Name: "app", Type: JOB_TYPE_FREESTYLE, Shell: "make"}, ""},
+		{Job{Name: "app", Type: JOB_TYPE_FREESTYLE}, ""},
+		{Job{Type: JOB_TYPE_PIPELINE, Script: "node {}"}, "job name not defined"},
+		{Job{Name: "team/app", Type: JOB_TYPE_PIPELINE, Script: "node {}"}, "must not contain a folder"},
+		{Job{Name: "app", Type: JOB_TYPE_PIPELINE}, "needs either a script or an scm"},
+		{
+			Job{Name: "app", Type: JOB_TYPE_PIPELINE, Script: "node {}", Scm: &Scm{Url: "https://git/app.git"}},
+			"needs either a script or an scm",
+		},
+		{Job{Name: "app", Type: JOB_TYPE_FREESTYLE, Script: "node {}"}, "use shell"},
+		{Job{Name: "app", Type: "matrix"}, "matrix is not accepted type"},
+		{Job{Name: "app", Type: JOB_TYPE_FREESTYLE, Scm: &Scm{}}, "scm url not defined"},
+		{
+			Job{Name: "app", Type: JOB_TYPE_FREESTYLE, Parameters: []Parameter{
+				{Name: "a", Type: PARAMETER_TYPE_STRING},
+				{Name: "b", Type: PARAMETER_TYPE_BOOLEAN},
+				{Name: "c", Type: PARAMETER_TYPE_BOOLEAN, Default: "true"},
+				{Name: "d", Type: PARAMETER_TYPE_CHOICE, Choices: []string{"x"}},
+			}},
+			"",
+		},
+		{
+			Job{Name: "app", Type: JOB_TYPE_FREESTYLE, Parameters: []Parameter{
+				{Name: "b", Type: PARAMETER_TYPE_BOOLEAN, Default: "yes"},
+			}},
+			`boolean parameter b has an invalid default "yes"`,
+		},
+		{
+			Job{Name: "app", Type: JOB_TYPE_FREESTYLE, Parameters: []Parameter{
+				{Name: "c", Type: PARAMETER_TYPE_CHOICE},
+			}},
+			"choice parameter c has no choices",
+		},
+		{
+			Job{Name: "app", Type: JOB_TYPE_FREESTYLE, Parameters: []Parameter{{Name: "f", Type: "file"}}},
+			"file is not accepted parameter type",
+		},
+	}
+	for _, test := range tests {
+		err := test.job.validate()
+		if test.wantErr == "" && err != nil {
+			t.Errorf("validate(%+v) error = %v, want nil", test.job, err)
+		}
+		if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+			t.Errorf("validate(%+v) error = %v, want %q", test.job, err, test.wantErr)
+		}
+	}
+}
+
+func TestFullName(t *testing.T) {
+	tests := []struct {
+		job  Job
+		want string
+	}{
+		{Job{Name: "app"}, "app"},
+		{Job{Name: "app", Folder: "team"}, "team/app"},
+		{Job{Name: "app", Folder: "/team/prod/"}, "team/prod/app"},
+	}
+	for _, test := range tests {
+		if got := test.job.FullName(); got != test.want {
+			t.Errorf("FullName(%+v) = %q, want %q", test.job, got, test.want)
+		}
+	}
+}
+
+func writeManifest(t *testing.T, dir string, name string, content string) {
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "b.yaml", "jobs:\n- name: web\n  folder: team\n  script: node {}\n")
+	writeManifest(t, dir, "a.yml", "jobs:\n- name: cleanup\n  type: freestyle\n  shell: make clean\n")
+	writeManifest(t, dir, "notes.txt", "not a manifest")
+
+	jobs, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	names := []string{}
+	for _, job := range jobs {
+		names = append(names, job.FullName()+":"+job.Type)
+	}
+	if got, want := strings.Join(names, ","), "cleanup:freestyle,team/web:pipeline"; got != want {
+		t.Errorf("Load() = %s, want %s", got, want)
+	}
+
+	writeManifest(t, dir, "c.yaml", "jobs:\n- name: web\n  folder: team\n  script: node {}\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "job team/web is defined in") {
+		t.Errorf("Load() error = %v, want a duplicate job error", err)
+	}
+
+	writeManifest(t, dir, "c.yaml", "jobs:\n- name: web\n  scripts: node {}\n")
+	if _, err := Load(dir); err == nil {
+		t.Errorf("Load() error = nil, want an unknown field error")
+	}
+}
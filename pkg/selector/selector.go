@@ -0,0 +1,125 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package selector implements the expression language used to select jobs,
+// for example:
+//
+//	status in (failure, unstable) && age < 2h && name =~ "api"
+//
+// An expression is compiled against a schema giving the type of each field,
+// so that type errors are reported before contacting Jenkins.
+package selector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Type int
+
+const (
+	TypeString Type = iota
+	TypeNumber
+	TypeDuration
+	TypeBool
+)
+
+func (typ Type) String() string {
+	switch typ {
+	case TypeString:
+		return "string"
+	case TypeNumber:
+		return "number"
+	case TypeDuration:
+		return "duration"
+	default:
+		return "boolean"
+	}
+}
+
+// Schema gives the type of the fields which can be used in an expression.
+type Schema map[string]Type
+
+func (schema Schema) fieldNames() []string {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Record is an item evaluated by a selector. Field returns a string, a
+// float64, a time.Duration or a bool depending on the type of the field in
+// the schema.
+type Record interface {
+	Field(name string) interface{}
+}
+
+// SyntaxError is returned when an expression can not be compiled, Pos is the
+// position of the offending token in the expression.
+type SyntaxError struct {
+	Expr string
+	Pos  int
+	Msg  string
+}
+
+func newSyntaxError(expr string, pos int, msg string) *SyntaxError {
+	return &SyntaxError{Expr: expr, Pos: pos, Msg: msg}
+}
+
+func (err *SyntaxError) Error() string {
+	return fmt.Sprintf(
+		"%s at position %d\n\t%s\n\t%s^",
+		err.Msg, err.Pos+1, err.Expr, strings.Repeat(" ", err.Pos),
+	)
+}
+
+type Selector struct {
+	expr string
+	root node
+}
+
+// Compile parses the expression and checks the fields and the type of the
+// values against the schema.
+func Compile(expr string, schema Schema) (*Selector, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{expr: expr, tokens: tokens, schema: schema}
+	if p.peek().kind == tokenEOF {
+		return nil, newSyntaxError(expr, 0, "empty expression")
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected %s", tok))
+	}
+	return &Selector{expr: expr, root: root}, nil
+}
+
+// Match reports whether the record matches the expression.
+func (selector *Selector) Match(record Record) bool {
+	return selector.root.eval(record)
+}
+
+func (selector *Selector) String() string {
+	return selector.expr
+}
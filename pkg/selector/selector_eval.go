@@ -0,0 +1,117 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selector
+
+import (
+	"regexp"
+	"time"
+)
+
+type node interface {
+	eval(record Record) bool
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n *andNode) eval(record Record) bool {
+	return n.left.eval(record) && n.right.eval(record)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n *orNode) eval(record Record) bool {
+	return n.left.eval(record) || n.right.eval(record)
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(record Record) bool {
+	return !n.operand.eval(record)
+}
+
+// compareNode compares a field with the values, there are several values
+// only for the in operator.
+type compareNode struct {
+	field  string
+	typ    Type
+	op     string
+	values []interface{}
+	regex  *regexp.Regexp
+}
+
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case time.Duration:
+		return float64(v)
+	default:
+		return 0
+	}
+}
+
+func (n *compareNode) eval(record Record) bool {
+	actual := record.Field(n.field)
+	switch n.op {
+	case "=~":
+		return n.regex.MatchString(actual.(string))
+	case "!~":
+		return !n.regex.MatchString(actual.(string))
+	case "in":
+		for _, value := range n.values {
+			if n.equal(actual, value) {
+				return true
+			}
+		}
+		return false
+	case "==":
+		return n.equal(actual, n.values[0])
+	case "!=":
+		return !n.equal(actual, n.values[0])
+	}
+
+	a, b := toFloat(actual), toFloat(n.values[0])
+	switch n.op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	default:
+		return a >= b
+	}
+}
+
+func (n *compareNode) equal(actual, value interface{}) bool {
+	switch n.typ {
+	case TypeNumber, TypeDuration:
+		return toFloat(actual) == toFloat(value)
+	default:
+		return actual == value
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+func (kind tokenKind) String() string {
+	switch kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenIdent:
+		return "identifier"
+	case tokenString:
+		return "string"
+	case tokenNumber:
+		return "number"
+	case tokenOperator:
+		return "operator"
+	case tokenLParen:
+		return "'('"
+	case tokenRParen:
+		return "')'"
+	default:
+		return "','"
+	}
+}
+
+type token struct {
+	kind tokenKind
+	// text is the token as written in the expression, except for strings
+	// where it is the unquoted value.
+	text string
+	pos  int
+}
+
+func (tok token) String() string {
+	switch tok.kind {
+	case tokenEOF:
+		return tok.kind.String()
+	case tokenString:
+		return strconv.Quote(tok.text)
+	default:
+		return fmt.Sprintf("'%s'", tok.text)
+	}
+}
+
+var operators = []string{"&&", "||", "==", "!=", "=~", "!~", "<=", ">=", "<", ">", "!"}
+
+func isIdentChar(r rune, first bool) bool {
+	if unicode.IsLetter(r) || r == '_' {
+		return true
+	}
+	return !first && (unicode.IsDigit(r) || r == '-' || r == '.' || r == '/')
+}
+
+// tokenize splits the expression in tokens, the last one being tokenEOF.
+func tokenize(expr string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(expr)
+	pos := 0
+	for pos < len(runes) {
+		r := runes[pos]
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "(", pos})
+			pos++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")", pos})
+			pos++
+		case r == ',':
+			tokens = append(tokens, token{tokenComma, ",", pos})
+			pos++
+		case r == '"' || r == '\'':
+			end := pos + 1
+			var value strings.Builder
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' && end+1 < len(runes) {
+					end++
+				}
+				value.WriteRune(runes[end])
+				end++
+			}
+			if end >= len(runes) {
+				return nil, newSyntaxError(expr, pos, "unterminated string")
+			}
+			tokens = append(tokens, token{tokenString, value.String(), pos})
+			pos = end + 1
+		case unicode.IsDigit(r):
+			end := pos
+			for end < len(runes) &&
+				(unicode.IsDigit(runes[end]) || unicode.IsLetter(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[pos:end]), pos})
+			pos = end
+		case isIdentChar(r, true):
+			end := pos
+			for end < len(runes) && isIdentChar(runes[end], end == pos) {
+				end++
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[pos:end]), pos})
+			pos = end
+		default:
+			operator := ""
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[pos:]), op) {
+					operator = op
+					break
+				}
+			}
+			if operator == "" {
+				return nil, newSyntaxError(expr, pos, fmt.Sprintf("unexpected character '%c'", r))
+			}
+			tokens = append(tokens, token{tokenOperator, operator, pos})
+			pos += len([]rune(operator))
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(runes)}), nil
+}
@@ -0,0 +1,251 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selector
+
+import (
+	"fmt"
+	"jenkinsctl/pkg/timeutil"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var operatorsByType = map[Type][]string{
+	TypeString:   {"==", "!=", "=~", "!~"},
+	TypeNumber:   {"==", "!=", "<", "<=", ">", ">="},
+	TypeDuration: {"==", "!=", "<", "<=", ">", ">="},
+	TypeBool:     {"==", "!="},
+}
+
+type parser struct {
+	expr   string
+	tokens []token
+	pos    int
+	schema Schema
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorAt(tok token, msg string) error {
+	return newSyntaxError(p.expr, tok.pos, msg)
+}
+
+// isKeyword reports whether the token is one of the operators given as
+// symbol or as word, like && and "and".
+func isKeyword(tok token, symbol, word string) bool {
+	return (tok.kind == tokenOperator && tok.text == symbol) ||
+		(tok.kind == tokenIdent && strings.EqualFold(tok.text, word))
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "||", "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "&&", "and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if isKeyword(p.peek(), "!", "not") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorAt(closing, fmt.Sprintf("expected ')', got %s", closing))
+		}
+		return inner, nil
+	case tokenIdent:
+		return p.parseComparison(tok)
+	default:
+		return nil, p.errorAt(tok, fmt.Sprintf("expected a field name, got %s", tok))
+	}
+}
+
+func (p *parser) parseComparison(field token) (node, error) {
+	typ, ok := p.schema[field.text]
+	if !ok {
+		return nil, p.errorAt(field, fmt.Sprintf(
+			"unknown field %s (known fields: %s)",
+			field, strings.Join(p.schema.fieldNames(), ", "),
+		))
+	}
+
+	op := p.peek()
+	switch {
+	case isKeyword(op, "", "in"):
+		p.next()
+		return p.parseIn(field, typ)
+	case isKeyword(op, "", "not") && isKeyword(p.peekAt(1), "", "in"):
+		p.next()
+		p.next()
+		in, err := p.parseIn(field, typ)
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{in}, nil
+	case op.kind == tokenOperator && op.text != "&&" && op.text != "||" && op.text != "!":
+		p.next()
+	case typ == TypeBool:
+		// A boolean field alone is true when the field is true.
+		return &compareNode{field: field.text, typ: typ, op: "==", values: []interface{}{true}}, nil
+	default:
+		return nil, p.errorAt(op, fmt.Sprintf("expected an operator after field %s, got %s", field, op))
+	}
+
+	supported := false
+	for _, candidate := range operatorsByType[typ] {
+		supported = supported || candidate == op.text
+	}
+	if !supported {
+		return nil, p.errorAt(op, fmt.Sprintf(
+			"operator %s is not supported on %s field %s (supported: %s)",
+			op, typ, field, strings.Join(operatorsByType[typ], " "),
+		))
+	}
+
+	valueTok := p.peek()
+	value, err := p.parseValue(field, typ)
+	if err != nil {
+		return nil, err
+	}
+	comparison := &compareNode{field: field.text, typ: typ, op: op.text, values: []interface{}{value}}
+	if op.text == "=~" || op.text == "!~" {
+		comparison.regex, err = regexp.Compile(value.(string))
+		if err != nil {
+			return nil, p.errorAt(valueTok, fmt.Sprintf("invalid regular expression: %s", err))
+		}
+	}
+	return comparison, nil
+}
+
+func (p *parser) parseIn(field token, typ Type) (node, error) {
+	if tok := p.next(); tok.kind != tokenLParen {
+		return nil, p.errorAt(tok, fmt.Sprintf("expected '(' after in, got %s", tok))
+	}
+	comparison := &compareNode{field: field.text, typ: typ, op: "in"}
+	for {
+		value, err := p.parseValue(field, typ)
+		if err != nil {
+			return nil, err
+		}
+		comparison.values = append(comparison.values, value)
+		tok := p.next()
+		if tok.kind == tokenRParen {
+			return comparison, nil
+		}
+		if tok.kind != tokenComma {
+			return nil, p.errorAt(tok, fmt.Sprintf("expected ',' or ')', got %s", tok))
+		}
+	}
+}
+
+func (p *parser) parseValue(field token, typ Type) (interface{}, error) {
+	tok := p.next()
+	mismatch := p.errorAt(tok, fmt.Sprintf(
+		"expected a %s value for field %s, got %s", typ, field, tok,
+	))
+	switch typ {
+	case TypeString:
+		if tok.kind == tokenString || tok.kind == tokenIdent || tok.kind == tokenNumber {
+			return tok.text, nil
+		}
+	case TypeNumber:
+		if tok.kind == tokenNumber {
+			value, err := strconv.ParseFloat(tok.text, 64)
+			if err != nil {
+				return nil, p.errorAt(tok, fmt.Sprintf("invalid number %s", tok))
+			}
+			return value, nil
+		}
+	case TypeDuration:
+		if tok.kind == tokenNumber {
+			value, err := timeutil.ParseDuration(tok.text)
+			if err != nil {
+				return nil, p.errorAt(tok, fmt.Sprintf(
+					"invalid duration %s, expected a value like 90s, 30m, 1h30m, 2d or 1w", tok,
+				))
+			}
+			return value, nil
+		}
+	case TypeBool:
+		if tok.kind == tokenIdent {
+			switch strings.ToLower(tok.text) {
+			case "true":
+				return true, nil
+			case "false":
+				return false, nil
+			}
+		}
+	}
+	return nil, mismatch
+}
@@ -0,0 +1,148 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selector
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSchema = Schema{
+	"name":     TypeString,
+	"status":   TypeString,
+	"builds":   TypeNumber,
+	"age":      TypeDuration,
+	"disabled": TypeBool,
+}
+
+type testRecord map[string]interface{}
+
+func (record testRecord) Field(name string) interface{} {
+	return record[name]
+}
+
+var testJob = testRecord{
+	"name":     "team/app-api",
+	"status":   "failure",
+	"builds":   float64(12),
+	"age":      90 * time.Minute,
+	"disabled": false,
+}
+
+func TestCompileMatch(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`name == "team/app-api"`, true},
+		{`name == team/app-api`, true},
+		{`name != 'team/app-api'`, false},
+		{`name =~ "^team/"`, true},
+		{`name !~ "api$"`, false},
+		{`status in (failure, unstable)`, true},
+		{`status not in (failure, unstable)`, false},
+		{`builds > 10`, true},
+		{`builds <= 11`, false},
+		{`builds in (1, 12)`, true},
+		{`age < 2h`, true},
+		{`age >= 1h30m`, true},
+		{`age > 1d`, false},
+		{`disabled`, false},
+		{`!disabled`, true},
+		{`disabled == false`, true},
+		{`disabled != TRUE`, true},
+		{`status == failure && age < 2h`, true},
+		{`status == success || builds > 10`, true},
+		{`status == success or builds > 100`, false},
+		{`not (status == success and builds > 10)`, true},
+		// && binds tighter than ||.
+		{`builds > 100 && status == failure || disabled == false`, true},
+		{`builds > 100 && (status == failure || disabled == false)`, false},
+	}
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			selector, err := Compile(test.expr, testSchema)
+			if err != nil {
+				t.Fatalf("Compile(%q) returned error: %s", test.expr, err)
+			}
+			if got := selector.Match(testJob); got != test.want {
+				t.Errorf("Match(%q) = %v, want %v", test.expr, got, test.want)
+			}
+			if selector.String() != test.expr {
+				t.Errorf("String() = %q, want %q", selector.String(), test.expr)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{``, 0, "empty expression"},
+		{`   `, 0, "empty expression"},
+		{`name == "api`, 8, "unterminated string"},
+		{`name @ api`, 5, "unexpected character '@'"},
+		{`owner == bob`, 0, "unknown field 'owner' (known fields: age, builds, disabled, name, status)"},
+		{`name`, 4, "expected an operator after field 'name', got end of expression"},
+		{`name < api`, 5, "operator '<' is not supported on string field 'name'"},
+		{`disabled > true`, 9, "operator '>' is not supported on boolean field 'disabled'"},
+		{`builds > many`, 9, "expected a number value for field 'builds', got 'many'"},
+		{`builds > 1.2.3`, 9, "invalid number '1.2.3'"},
+		{`age < 2x`, 6, "invalid duration '2x'"},
+		{`disabled == yes`, 12, "expected a boolean value for field 'disabled', got 'yes'"},
+		{`name =~ "("`, 8, "invalid regular expression"},
+		{`status in failure`, 10, "expected '(' after in, got 'failure'"},
+		{`status in (failure unstable)`, 19, "expected ',' or ')', got 'unstable'"},
+		{`(builds > 1`, 11, "expected ')', got end of expression"},
+		{`builds > 1 builds`, 11, "unexpected 'builds'"},
+		{`&& builds > 1`, 0, "expected a field name, got '&&'"},
+		{`builds > 1 ||`, 13, "expected a field name, got end of expression"},
+	}
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			_, err := Compile(test.expr, testSchema)
+			if err == nil {
+				t.Fatalf("Compile(%q) returned no error", test.expr)
+			}
+			var syntaxError *SyntaxError
+			if !errors.As(err, &syntaxError) {
+				t.Fatalf("Compile(%q) returned %T, want *SyntaxError", test.expr, err)
+			}
+			if syntaxError.Pos != test.pos {
+				t.Errorf("Pos = %d, want %d", syntaxError.Pos, test.pos)
+			}
+			if !strings.HasPrefix(syntaxError.Msg, test.msg) {
+				t.Errorf("Msg = %q, want prefix %q", syntaxError.Msg, test.msg)
+			}
+		})
+	}
+}
+
+func TestSyntaxErrorMessage(t *testing.T) {
+	_, err := Compile(`builds > many`, testSchema)
+	want := "expected a number value for field 'builds', got 'many' at position 10\n" +
+		"\tbuilds > many\n" +
+		"\t         ^"
+	if err == nil || err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err, want)
+	}
+}
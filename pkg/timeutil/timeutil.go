@@ -0,0 +1,103 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package timeutil parses the durations and dates given on the command line.
+package timeutil
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationRegex = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)(ns|us|ms|s|m|h|d|w)`)
+
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+}
+
+// ParseDuration parses a duration like time.ParseDuration, with the days (d)
+// and weeks (w) units in addition, for example 90s, 1h30m, 2d or 1w. The
+// durations over about 292 years are rejected, like time.ParseDuration does.
+func ParseDuration(text string) (time.Duration, error) {
+	if text == "" {
+		return 0, fmt.Errorf("invalid duration %q", text)
+	}
+	var duration time.Duration
+	for rest := text; rest != ""; {
+		match := durationRegex.FindStringSubmatch(rest)
+		if match == nil {
+			return 0, fmt.Errorf("invalid duration %q", text)
+		}
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", text)
+		}
+		part := value * float64(durationUnits[match[2]])
+		if part >= math.MaxInt64 || time.Duration(part) > math.MaxInt64-duration {
+			return 0, fmt.Errorf("duration %q is too large", text)
+		}
+		duration += time.Duration(part)
+		rest = rest[len(match[0]):]
+	}
+	return duration, nil
+}
+
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseTime parses an absolute date in local time, like 2026-10-01T08:00, one
+// of the words now, today and yesterday, or a duration before now, like 2h or
+// 3d ago.
+func ParseTime(text string, now time.Time) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(text) {
+	case "now":
+		return now, nil
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	for _, layout := range timeLayouts {
+		if date, err := time.ParseInLocation(layout, text, now.Location()); err == nil {
+			return date, nil
+		}
+	}
+	if duration, err := ParseDuration(strings.TrimSpace(strings.TrimSuffix(text, "ago"))); err == nil {
+		return now.Add(-duration), nil
+	}
+	return time.Time{}, fmt.Errorf(
+		"invalid date %q, expected a date like 2026-10-01T08:00, today, yesterday or 2h ago", text,
+	)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2021 Alexis Ries <ries.alexis@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    time.Duration
+		wantErr bool
+	}{
+		{text: "90s", want: 90 * time.Second},
+		{text: "45m", want: 45 * time.Minute},
+		{text: "1h30m", want: 90 * time.Minute},
+		{text: "1.5h", want: 90 * time.Minute},
+		{text: "2d", want: 48 * time.Hour},
+		{text: "1w", want: 7 * 24 * time.Hour},
+		{text: "1w2d3h", want: (9*24 + 3) * time.Hour},
+		{text: "250ms", want: 250 * time.Millisecond},
+		{text: "106751d", want: 106751 * 24 * time.Hour},
+		{text: "", wantErr: true},
+		{text: "abc", wantErr: true},
+		{text: "10", wantErr: true},
+		{text: "2x", wantErr: true},
+		{text: "2d ", wantErr: true},
+		{text: "-2d", wantErr: true},
+		{text: "99999999999w", wantErr: true},
+		{text: "106752d", wantErr: true},
+		{text: "106751d1d", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseDuration(test.text)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v, error %v", test.text, got, err, test.want, test.wantErr)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	location := time.FixedZone("CEST", 2*3600)
+	now := time.Date(2026, 10, 19, 15, 30, 0, 0, location)
+	tests := []struct {
+		text    string
+		want    time.Time
+		wantErr bool
+	}{
+		{text: "now", want: now},
+		{text: "today", want: time.Date(2026, 10, 19, 0, 0, 0, 0, location)},
+		{text: "Yesterday", want: time.Date(2026, 10, 18, 0, 0, 0, 0, location)},
+		{text: "2026-10-01", want: time.Date(2026, 10, 1, 0, 0, 0, 0, location)},
+		{text: "2026-10-01T08:00", want: time.Date(2026, 10, 1, 8, 0, 0, 0, location)},
+		{text: "2026-10-01 08:00:30", want: time.Date(2026, 10, 1, 8, 0, 30, 0, location)},
+		{text: "2026-10-01T08:00:00Z", want: time.Date(2026, 10, 1, 10, 0, 0, 0, location)},
+		{text: "2h ago", want: now.Add(-2 * time.Hour)},
+		{text: " 3d ", want: now.Add(-72 * time.Hour)},
+		{text: "1w ago", want: now.Add(-7 * 24 * time.Hour)},
+		{text: "", wantErr: true},
+		{text: "2026-13-01", wantErr: true},
+		{text: "last week", wantErr: true},
+		{text: "99999999999w ago", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseTime(test.text, now)
+		if (err != nil) != test.wantErr || !got.Equal(test.want) {
+			t.Errorf("ParseTime(%q) = %v, %v, want %v, error %v", test.text, got, err, test.want, test.wantErr)
+		}
+	}
+}